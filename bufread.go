@@ -0,0 +1,67 @@
+package hasher
+
+import (
+	"context"
+	"io"
+)
+
+// bufferedReader fills a buffer with as many reads of the underlying reader as needed so that the copy sees large reads
+// even if the underlying reader returns tiny reads(e.g. a decrypting reader returning 16 bytes at a time).
+// It stops filling the buffer when the context is canceled, so cancelation is not delayed until the buffer is full.
+// A single blocking read of the underlying reader still delays the cancelation.
+type bufferedReader struct {
+	ctx        context.Context
+	r          io.Reader
+	buf        []byte
+	start, end int
+	err        error
+}
+
+// newBufferedReader returns a buffered reader with the given buffer size.
+func newBufferedReader(ctx context.Context, r io.Reader, size int) *bufferedReader {
+	return &bufferedReader{
+		ctx: ctx,
+		r:   r,
+		buf: make([]byte, size),
+	}
+}
+
+// fill reads the underlying reader until the buffer is full, an error occurs or the context is canceled.
+func (r *bufferedReader) fill() {
+	r.start, r.end = 0, 0
+
+	for r.end < len(r.buf) && r.err == nil {
+		if err := r.ctx.Err(); err != nil {
+			r.err = err
+			break
+		}
+
+		n, err := r.r.Read(r.buf[r.end:])
+		r.end += n
+		r.err = err
+	}
+}
+
+// Read implements [io.Reader] interface.
+// The error of the underlying reader is returned after the buffered bytes are drained.
+func (r *bufferedReader) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if r.start == r.end {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.fill()
+	}
+
+	n = copy(p, r.buf[r.start:r.end])
+	r.start += n
+
+	if n == 0 {
+		return 0, r.err
+	}
+
+	return n, nil
+}
@@ -0,0 +1,112 @@
+package hasher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNonStandardChecksums indicates that an option changes the checksums or their keys(e.g. [Prefix] or [MultihashNames]).
+	ErrNonStandardChecksums = errors.New("non-standard checksums")
+)
+
+// CacheEntry is the cached checksums of a file.
+type CacheEntry struct {
+	// ModTime is the modification time of the file when the checksums were computed.
+	ModTime time.Time
+	// Size is the size of the file when the checksums were computed.
+	Size int64
+	// Checksums stores the checksums. key: algorithm, value: checksum.
+	Checksums map[string][]byte
+}
+
+// Cache stores the checksums of files.
+// Users can implement it with any store(e.g. memory, database or file).
+type Cache interface {
+	// Get returns the cache entry of the file.
+	// It returns false if no entry found.
+	Get(filename string) (CacheEntry, bool)
+	// Put stores the cache entry of the file.
+	Put(filename string, entry CacheEntry) error
+}
+
+// CachedFileChecksums returns the checksums of the file from the cache
+// if the modification time and size of the file are not changed
+// and the cached entry contains all the hash algorithms.
+// Otherwise, it calls [FileChecksums] to compute the checksums and puts them into the cache.
+// It's used to speed up repeated scans of files.
+// ctx: [context.Context].
+// filename: file to calculate the hash checksums.
+// cache: cache to get and put the checksums.
+// options: [Option] used to set hash algorithms or report progress.
+// It returns [ErrNonStandardChecksums] if any option changes the checksums or their keys,
+// because the cache entry does not record these options.
+// See [AppendLength], [Prefix], [SizeEntry] and [MultihashNames].
+// recomputed: false if the checksums are returned from the cache.
+// If the context is canceled or the deadline expires, it returns the error and nothing is cached.
+func CachedFileChecksums(ctx context.Context, filename string, cache Cache, options ...Option) (checksums map[string][]byte, recomputed bool, err error) {
+	// Set options.
+	c := &calculator{}
+	for _, option := range options {
+		option(c)
+	}
+
+	if len(c.algs) == 0 {
+		c.algs = DefaultAlgs
+	}
+
+	if c.nonStandard() {
+		return nil, false, ErrNonStandardChecksums
+	}
+
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if entry, ok := cache.Get(filename); ok && entry.Size == fi.Size() && entry.ModTime.Equal(fi.ModTime()) {
+		checksums = make(map[string][]byte)
+		for _, alg := range c.algs {
+			alg = strings.ToUpper(alg)
+			if alg == NoneAlg {
+				continue
+			}
+
+			sum, ok := entry.Checksums[alg]
+			if !ok {
+				checksums = nil
+				break
+			}
+			checksums[alg] = sum
+		}
+
+		if checksums != nil {
+			return checksums, false, nil
+		}
+	}
+
+	_, checksums, err = FileChecksums(ctx, filename, options...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	entry := CacheEntry{
+		ModTime:   fi.ModTime(),
+		Size:      fi.Size(),
+		Checksums: checksums,
+	}
+
+	if err = cache.Put(filename, entry); err != nil {
+		return nil, false, err
+	}
+
+	return checksums, true, nil
+}
+
+// nonStandard returns true if the options change the checksums or their keys.
+func (c *calculator) nonStandard() bool {
+	return c.appendLength || len(c.prefix) != 0 || c.sizeEntry || c.multihashNames
+}
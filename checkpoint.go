@@ -0,0 +1,70 @@
+package hasher
+
+import (
+	"encoding/gob"
+	"errors"
+	"io"
+	"sort"
+)
+
+// checkpointVersion is the version of the checkpoint format.
+const checkpointVersion = 1
+
+var (
+	// ErrCheckpointVersion indicates that the version of the checkpoint is not supported.
+	ErrCheckpointVersion = errors.New("unsupported checkpoint version")
+)
+
+// Checkpoint stores the states of a stopped calculation to resume it in a later process.
+// It's used by long-running jobs to survive process restarts.
+// Get the states from [Checksums](or other functions) when the context is canceled,
+// or from [Session.Snapshot].
+type Checkpoint struct {
+	// Version is the version of the checkpoint format. It's set by [SaveCheckpoint].
+	Version int
+	// Source is the identity of the source(e.g. file name or URL).
+	// It's used by the caller to reattach the source.
+	Source string
+	// Hashed is the number of bytes calculated previously.
+	// The caller reattaches the source at this offset.
+	Hashed int64
+	// States stores the states of the hashes. key: algorithm, value: binary state.
+	States map[string][]byte
+}
+
+// Options returns the options to resume the calculation: [Algs] and [States].
+func (cp *Checkpoint) Options() []Option {
+	var algs []string
+	for alg := range cp.States {
+		algs = append(algs, alg)
+	}
+	sort.Strings(algs)
+
+	return []Option{Algs(algs), States(cp.Hashed, cp.States)}
+}
+
+// SaveCheckpoint writes the checkpoint to w.
+// The format is [encoding/gob] of [Checkpoint] with the current version.
+func SaveCheckpoint(w io.Writer, cp *Checkpoint) error {
+	saved := *cp
+	saved.Version = checkpointVersion
+
+	return gob.NewEncoder(w).Encode(&saved)
+}
+
+// LoadCheckpoint reads the checkpoint written by [SaveCheckpoint] from r.
+// It returns [ErrCheckpointVersion] if the version is not supported.
+// The caller reattaches the source at the offset of [Checkpoint.Hashed],
+// and passes [Checkpoint.Options] to resume the calculation.
+func LoadCheckpoint(r io.Reader) (*Checkpoint, error) {
+	cp := &Checkpoint{}
+	if err := gob.NewDecoder(r).Decode(cp); err != nil {
+		return nil, err
+	}
+
+	if cp.Version != checkpointVersion {
+		return nil, ErrCheckpointVersion
+	}
+
+	return cp, nil
+}
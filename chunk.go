@@ -0,0 +1,196 @@
+package hasher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/northbright/iocopy"
+)
+
+var (
+	// ErrInvalidChunkSize indicates that the chunk size is invalid.
+	ErrInvalidChunkSize = errors.New("invalid chunk size")
+
+	// ErrInvalidSize indicates that the size is invalid.
+	ErrInvalidSize = errors.New("invalid size")
+
+	// ErrInvalidDigestsLength indicates that the length of the concatenated checksums is not a multiple of the checksum size.
+	ErrInvalidDigestsLength = errors.New("invalid digests length")
+)
+
+// MerkleRoot returns the Merkle root of the leaves.
+// alg: hash algorithm used to combine the nodes.
+// leaves: checksums of the chunks in order.
+// Each parent node is H(left || right).
+// If a level has an odd number of nodes, the last node is promoted to the next level unchanged.
+// It returns the leaf itself if there's only one leaf,
+// and the checksum of empty data if there's no leaf.
+func MerkleRoot(alg string, leaves [][]byte) ([]byte, error) {
+	h, err := newHash(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(leaves) == 0 {
+		return h.Sum(nil), nil
+	}
+
+	nodes := leaves
+	for len(nodes) > 1 {
+		var parents [][]byte
+
+		for i := 0; i < len(nodes); i += 2 {
+			if i+1 == len(nodes) {
+				parents = append(parents, nodes[i])
+				break
+			}
+
+			h.Reset()
+			h.Write(nodes[i])
+			h.Write(nodes[i+1])
+			parents = append(parents, h.Sum(nil))
+		}
+
+		nodes = parents
+	}
+
+	return nodes[0], nil
+}
+
+// ChunkedChecksums reads r and returns the checksums of each chunk and the Merkle root of them.
+// ctx: [context.Context].
+// alg: hash algorithm.
+// r: read the bytes from r and calculate the checksums.
+// chunkSize: size of each chunk. The last chunk may be shorter.
+// leaves: checksums of the chunks in order.
+// root: Merkle root of the leaves. See [MerkleRoot].
+func ChunkedChecksums(ctx context.Context, alg string, r io.Reader, chunkSize int64) (leaves [][]byte, root []byte, err error) {
+	if chunkSize <= 0 {
+		return nil, nil, ErrInvalidChunkSize
+	}
+
+	h, err := newHash(alg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for {
+		h.Reset()
+
+		n, err := iocopy.Copy(ctx, h, io.LimitReader(r, chunkSize))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// No more data.
+		if n == 0 {
+			break
+		}
+
+		leaves = append(leaves, h.Sum(nil))
+
+		// Last chunk.
+		if n < chunkSize {
+			break
+		}
+	}
+
+	root, err = MerkleRoot(alg, leaves)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return leaves, root, nil
+}
+
+// ChunkedChecksumsParallel is the parallel version of [ChunkedChecksums].
+// It reads disjoint chunks of ra concurrently and returns the checksums of each chunk and the Merkle root of them.
+// It's used for a single huge file on fast storage.
+// ctx: [context.Context].
+// alg: hash algorithm.
+// ra: read the chunks from ra by [io.NewSectionReader].
+// size: total size of ra. It returns [ErrInvalidSize] if it's negative.
+// chunkSize: size of each chunk. The last chunk may be shorter.
+// It returns [ErrInvalidChunkSize] if it's <= 0 or the number of chunks overflows int.
+// concurrency: max number of chunks calculated at the same time.
+// It uses [runtime.NumCPU] if concurrency <= 0.
+// leaves: checksums of the chunks in file order regardless of completion order.
+// root: Merkle root of the leaves. See [MerkleRoot].
+func ChunkedChecksumsParallel(ctx context.Context, alg string, ra io.ReaderAt, size int64, chunkSize int64, concurrency int) (leaves [][]byte, root []byte, err error) {
+	if chunkSize <= 0 {
+		return nil, nil, ErrInvalidChunkSize
+	}
+
+	if size < 0 {
+		return nil, nil, ErrInvalidSize
+	}
+
+	// Check hash algorithm.
+	if _, err = newHash(alg); err != nil {
+		return nil, nil, err
+	}
+
+	// Avoid overflow of size + chunkSize - 1.
+	count := size / chunkSize
+	if size%chunkSize != 0 {
+		count++
+	}
+
+	// Too many chunks to index on 32-bit platforms.
+	if count > math.MaxInt {
+		return nil, nil, ErrInvalidChunkSize
+	}
+
+	leaves = make([][]byte, count)
+
+	err = parallel(ctx, int(count), concurrency, func(ctx context.Context, index int) error {
+		h, _ := newHash(alg)
+
+		off := int64(index) * chunkSize
+		n := min(chunkSize, size-off)
+
+		if _, err := iocopy.Copy(ctx, h, io.NewSectionReader(ra, off, n)); err != nil {
+			return err
+		}
+
+		leaves[index] = h.Sum(nil)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root, err = MerkleRoot(alg, leaves)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return leaves, root, nil
+}
+
+// SplitDigests splits the concatenated checksums into pieces of the checksum size of the hash algorithm.
+// It's used for piece hash lists(e.g. BitTorrent) and Merkle leaves.
+// concatenated: concatenated checksums.
+// alg: hash algorithm of the checksums.
+// It returns [ErrInvalidDigestsLength] if the length of concatenated is not a multiple of the checksum size.
+// The returned pieces share memory with concatenated.
+func SplitDigests(concatenated []byte, alg string) ([][]byte, error) {
+	h, err := newHash(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	size := h.Size()
+	if len(concatenated)%size != 0 {
+		return nil, ErrInvalidDigestsLength
+	}
+
+	var digests [][]byte
+	for i := 0; i < len(concatenated); i += size {
+		digests = append(digests, concatenated[i:i+size:i+size])
+	}
+
+	return digests, nil
+}
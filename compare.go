@@ -0,0 +1,104 @@
+package hasher
+
+import (
+	"context"
+	"crypto/subtle"
+	"io"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// CompareChecksums compares two checksum maps returned by [Checksums] or other functions.
+// a, b: checksum maps. key: algorithm, value: checksum.
+// matched: algorithms in both maps with the same checksums.
+// mismatched: algorithms in both maps with different checksums.
+// onlyInA: algorithms only in a.
+// onlyInB: algorithms only in b.
+// Checksums are compared in constant time.
+// All returned algorithms are sorted by names.
+func CompareChecksums(a, b map[string][]byte) (matched []string, mismatched []string, onlyInA []string, onlyInB []string) {
+	for alg, sumA := range a {
+		sumB, ok := b[alg]
+		if !ok {
+			onlyInA = append(onlyInA, alg)
+			continue
+		}
+
+		if subtle.ConstantTimeCompare(sumA, sumB) == 1 {
+			matched = append(matched, alg)
+		} else {
+			mismatched = append(mismatched, alg)
+		}
+	}
+
+	for alg := range b {
+		if _, ok := a[alg]; !ok {
+			onlyInB = append(onlyInB, alg)
+		}
+	}
+
+	sort.Strings(matched)
+	sort.Strings(mismatched)
+	sort.Strings(onlyInA)
+	sort.Strings(onlyInB)
+
+	return matched, mismatched, onlyInA, onlyInB
+}
+
+// LeadingZeroBits returns the number of leading zero bits of the checksum.
+// It's used for proof-of-work or vanity-hash checks and is algorithm-agnostic.
+// sum: raw checksum.
+func LeadingZeroBits(sum []byte) int {
+	n := 0
+
+	for _, b := range sum {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+
+	return n
+}
+
+// HasPrefixBits reports whether the checksum has at least zeroBits leading zero bits.
+// It's used for difficulty checks of proof-of-work.
+// sum: raw checksum.
+// zeroBits: number of leading zero bits required.
+func HasPrefixBits(sum []byte, zeroBits int) bool {
+	return LeadingZeroBits(sum) >= zeroBits
+}
+
+// StreamsEqual reports whether the two streams have the same content by comparing their checksums.
+// It's used to check whether a copy preserves the content without buffering either stream fully.
+// ctx: [context.Context].
+// alg: hash algorithm.
+// a, b: streams to compare. They're hashed concurrently in goroutines.
+// If hashing one stream fails, hashing the other one is canceled.
+// The checksums are compared in constant time.
+func StreamsEqual(ctx context.Context, alg string, a, b io.Reader) (bool, error) {
+	// Check hash algorithm.
+	if _, err := newHash(alg); err != nil {
+		return false, err
+	}
+
+	alg = strings.ToUpper(alg)
+	readers := []io.Reader{a, b}
+	sums := make([][]byte, len(readers))
+
+	err := parallel(ctx, len(readers), len(readers), func(ctx context.Context, i int) error {
+		_, checksums, err := Checksums(ctx, readers[i], -1, Algs([]string{alg}))
+		if err != nil {
+			return err
+		}
+
+		sums[i] = checksums[alg]
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare(sums[0], sums[1]) == 1, nil
+}
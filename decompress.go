@@ -0,0 +1,57 @@
+package hasher
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"io"
+)
+
+var (
+	// gzipMagic is the magic bytes of gzip format.
+	gzipMagic = []byte{0x1f, 0x8b}
+
+	// bzip2Magic is the magic bytes of bzip2 format.
+	bzip2Magic = []byte("BZh")
+)
+
+// DecompressedChecksums sniffs the magic bytes of r, decompresses it transparently,
+// and returns the checksums of given hash algorithms of the decompressed content.
+// It's used for mixed inputs which may be compressed or plain.
+// Recognized formats:
+//   - gzip(magic: 1f 8b).
+//   - bzip2(magic: "BZh").
+//
+// Unknown formats(including xz, which is not supported by the standard library) are hashed as raw bytes.
+// ctx: [context.Context].
+// It returns states of the hashes instead of checksums
+// if the context is canceled or the deadline expires.
+// The states can't be used to resume the calculation because the decompressor's state is not saved.
+// r: read the bytes from r.
+// options: [Option] used to set hash algorithms or report progress.
+// The total size of the decompressed content is unknown, so the percent reported by the callback is always 0.
+// written: number of decompressed bytes.
+func DecompressedChecksums(ctx context.Context, r io.Reader, options ...Option) (written int64, checksums map[string][]byte, err error) {
+	br := bufio.NewReader(r)
+
+	// Peek returns fewer bytes and an error for short inputs, which are treated as raw bytes.
+	magic, _ := br.Peek(len(bzip2Magic))
+
+	var reader io.Reader = br
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return 0, nil, err
+		}
+		defer zr.Close()
+		reader = zr
+	case bytes.HasPrefix(magic, bzip2Magic):
+		reader = bzip2.NewReader(br)
+	}
+
+	return Checksums(ctx, reader, -1, options...)
+}
@@ -0,0 +1,52 @@
+package hasher
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+)
+
+// FindDuplicates hashes the files concurrently and groups the files with identical content.
+// It's used to build dedup reports.
+// ctx: [context.Context]. All workers stop when it's canceled.
+// alg: hash algorithm.
+// paths: files to hash.
+// concurrency: max number of files hashed at the same time.
+// It uses [runtime.NumCPU] if concurrency <= 0.
+// It returns a map from hex checksum to the paths sharing it.
+// Only groups with more than 1 file are included and the paths keep the order in paths.
+func FindDuplicates(ctx context.Context, alg string, paths []string, concurrency int) (map[string][]string, error) {
+	// Check hash algorithm.
+	if _, err := newHash(alg); err != nil {
+		return nil, err
+	}
+
+	alg = strings.ToUpper(alg)
+	sums := make([]string, len(paths))
+
+	err := parallel(ctx, len(paths), concurrency, func(ctx context.Context, i int) error {
+		_, checksums, err := FileChecksums(ctx, paths[i], Algs([]string{alg}))
+		if err != nil {
+			return err
+		}
+
+		sums[i] = hex.EncodeToString(checksums[alg])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]string)
+	for i, sum := range sums {
+		groups[sum] = append(groups[sum], paths[i])
+	}
+
+	for sum, group := range groups {
+		if len(group) < 2 {
+			delete(groups, sum)
+		}
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,29 @@
+//go:build !hasher_whirlpool
+
+package hasher_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/hasher"
+)
+
+// The output differs when optional hash algorithms are enabled by build tags.
+func ExampleSupportedHashAlgs() {
+	algs := hasher.SupportedHashAlgs()
+	l := len(algs)
+
+	for i, alg := range algs {
+		fmt.Printf("%v: %v", i, alg)
+		if i != l-1 {
+			fmt.Printf("\n")
+		}
+	}
+
+	// Output:
+	// 0: CRC-32
+	// 1: MD5
+	// 2: SHA-1
+	// 3: SHA-256
+	// 4: SHA-512
+}
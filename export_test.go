@@ -0,0 +1,17 @@
+package hasher
+
+import "time"
+
+// SetNowFunc replaces the clock used by the package and returns a function to restore it.
+func SetNowFunc(f func() time.Time) (restore func()) {
+	old := nowFunc
+	nowFunc = f
+	return func() { nowFunc = old }
+}
+
+// UnregisterHashAlg removes the hash algorithm registered by RegisterHashAlg in tests.
+func UnregisterHashAlg(alg string) {
+	hashAlgsLock.Lock()
+	defer hashAlgsLock.Unlock()
+	delete(hashAlgsToNewFuncs, alg)
+}
@@ -0,0 +1,51 @@
+package hasher
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrInvalidTruncateLength indicates that the length to truncate the checksum is invalid.
+	ErrInvalidTruncateLength = errors.New("invalid truncate length")
+)
+
+// TruncateChecksum returns the first n bytes of the checksum.
+// It's used for compact identifiers(e.g. short git-style hashes).
+// sum: checksum.
+// n: number of bytes to keep. It should be in the range [0, len(sum)].
+// It returns [ErrInvalidTruncateLength] if n is out of range.
+// The returned slice is a copy and does not share memory with sum.
+func TruncateChecksum(sum []byte, n int) ([]byte, error) {
+	if n < 0 || n > len(sum) {
+		return nil, ErrInvalidTruncateLength
+	}
+
+	return append([]byte{}, sum[:n]...), nil
+}
+
+// TruncateChecksumHex returns the hex string of the first n bytes of the checksum.
+// sum: checksum.
+// n: number of bytes to keep. It should be in the range [0, len(sum)].
+// It returns [ErrInvalidTruncateLength] if n is out of range.
+func TruncateChecksumHex(sum []byte, n int) (string, error) {
+	truncated, err := TruncateChecksum(sum, n)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(truncated), nil
+}
+
+// FormatChecksumLine returns a line of checksum files in the format of sha256sum: "<hex>  <filename>\n".
+// It can be parsed by [ParseChecksumLine].
+func FormatChecksumLine(sum []byte, filename string) string {
+	return hex.EncodeToString(sum) + "  " + filename + "\n"
+}
+
+// SidecarExt returns the file extension of the sidecar checksum file of the hash algorithm.
+// It's the lower case name of the algorithm without "-"(e.g. ".sha256" for "SHA-256").
+func SidecarExt(alg string) string {
+	return "." + strings.ToLower(strings.ReplaceAll(alg, "-", ""))
+}
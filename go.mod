@@ -3,6 +3,7 @@ module github.com/northbright/hasher
 go 1.23.0
 
 require (
+	github.com/jzelinskie/whirlpool v0.0.0-20201016144138-0675e54bb004
 	github.com/northbright/download v0.0.16
 	github.com/northbright/httputil v1.2.3
 	github.com/northbright/iocopy v1.13.7
@@ -7,13 +7,17 @@ import (
 	"crypto/sha256"
 	"crypto/sha512"
 	"encoding"
+	"encoding/binary"
 	"errors"
+	"fmt"
 	"hash"
 	"hash/crc32"
 	"io"
+	"log/slog"
 	"os"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/northbright/httputil"
@@ -27,7 +31,24 @@ func crc32NewIEEE() hash.Hash {
 	return hash.Hash(crc32.NewIEEE())
 }
 
+// NoneAlg is the sentinel "NONE" algorithm.
+// It counts the bytes but produces no checksum entry.
+// It's used by callers which toggle hashing via configuration without branching.
+// It can be combined with real hash algorithms and just contributes a byte count.
+const NoneAlg = "NONE"
+
+// SizeKey is the reserved key of the synthetic entry inserted by [SizeEntry] in the checksums map.
+// It's not a hash algorithm and can't be registered by [RegisterHashAlg].
+const SizeKey = "SIZE"
+
+// nowFunc returns the current time.
+// Tests replace it to get deterministic timestamps and durations.
+var nowFunc = time.Now
+
 var (
+	// hashAlgsLock protects hashAlgsToNewFuncs.
+	hashAlgsLock sync.RWMutex
+
 	hashAlgsToNewFuncs = map[string]func() hash.Hash{
 		"MD5":     md5.New,
 		"SHA-1":   sha1.New,
@@ -50,30 +71,115 @@ var (
 
 	// Not encoding.BinaryUnmarshaler
 	ErrNotBinaryUnmarshaler = errors.New("not binary unmarshaler")
+
+	// ErrInvalidHashAlg indicates that the name or the new function of the hash algorithm to register is invalid.
+	ErrInvalidHashAlg = errors.New("invalid hash algorithm")
+
+	// ErrHashAlgExists indicates that the hash algorithm is already registered.
+	ErrHashAlgExists = errors.New("hash algorithm already exists")
+
+	// ErrShortRead indicates that fewer bytes than the expected size are read.
+	ErrShortRead = errors.New("short read")
+
+	// ErrLongRead indicates that more bytes than the expected size are read.
+	ErrLongRead = errors.New("long read")
+
+	// ErrInvalidRange indicates that the byte range is out of the file.
+	ErrInvalidRange = errors.New("invalid range")
+
+	// OnComplete is the hook called at the end of each calculation of [ChecksumsBuffer] if it's not nil.
+	// It's used for observability(e.g. metrics and tracing).
+	// algs: hash algorithms.
+	// n: number of bytes calculated in current calculation.
+	// d: duration of current calculation.
+	// err: error returned by the calculation.
+	// It runs synchronously on the goroutine which calls [ChecksumsBuffer],
+	// so it should return quickly.
+	OnComplete func(algs []string, n int64, d time.Duration, err error)
 )
 
-// SupportedHashAlgs returns supported hash algorithms of this package.
+// SupportedHashAlgs returns supported hash algorithms of this package,
+// including the ones registered by [RegisterHashAlg].
+// The result is sorted by names byte-wise(locale-independent),
+// so it's stable and does not depend on the registration order.
 func SupportedHashAlgs() []string {
 	var algs []string
 
+	hashAlgsLock.RLock()
 	for alg := range hashAlgsToNewFuncs {
 		algs = append(algs, alg)
 	}
+	hashAlgsLock.RUnlock()
 
-	// Sort hash algorithms by names.
-	sort.Slice(algs, func(i, j int) bool {
-		return algs[i] < algs[j]
-	})
+	// Sort hash algorithms by names byte-wise.
+	sort.Strings(algs)
 
 	return algs
 }
 
+// RegisterHashAlg registers a hash algorithm.
+// It's used to support niche hash algorithms(e.g. Whirlpool).
+// alg: name of the hash algorithm. It's converted to upper case letters.
+// f: function to new a [hash.Hash] of the hash algorithm.
+// It returns [ErrInvalidHashAlg] if alg is empty, [NoneAlg] or [SizeKey], or f is nil.
+// It returns [ErrHashAlgExists] if the hash algorithm is already registered.
+func RegisterHashAlg(alg string, f func() hash.Hash) error {
+	alg = strings.ToUpper(alg)
+	if alg == "" || alg == NoneAlg || alg == SizeKey || f == nil {
+		return ErrInvalidHashAlg
+	}
+
+	hashAlgsLock.Lock()
+	defer hashAlgsLock.Unlock()
+
+	if _, ok := hashAlgsToNewFuncs[alg]; ok {
+		return ErrHashAlgExists
+	}
+
+	hashAlgsToNewFuncs[alg] = f
+	return nil
+}
+
+// newHash returns a new [hash.Hash] of the given hash algorithm.
+// alg: name of hash algorithm. It's case-insensitive.
+func newHash(alg string) (hash.Hash, error) {
+	hashAlgsLock.RLock()
+	f, ok := hashAlgsToNewFuncs[strings.ToUpper(alg)]
+	hashAlgsLock.RUnlock()
+	if !ok {
+		return nil, ErrUnSupportedHashAlg
+	}
+
+	return f(), nil
+}
+
 type calculator struct {
-	algs     []string
-	hashed   int64
-	states   map[string][]byte
-	fn       OnHashFunc
-	interval time.Duration
+	algs         []string
+	hashed       int64
+	states       map[string][]byte
+	fn           OnHashFunc
+	interval     time.Duration
+	appendLength bool
+	rounder      func(float64) float32
+	prefix       []byte
+	everyTick    bool
+	rateLimit    int64
+	readBufSize  int
+	logger       *slog.Logger
+	isolate      bool
+	expectSize   bool
+	expectedSize int64
+	onFinalize   func(algs []string)
+	sizeEntry    bool
+	partial      bool
+
+	multihashNames bool
+
+	minThroughput    int64
+	throughputWindow time.Duration
+
+	onSizeExceeded   func(total, n int64)
+	sizeExceededOnce sync.Once
 }
 
 // Option sets optional parameters to report progress.
@@ -82,8 +188,10 @@ type Option func(c *calculator)
 // Algs returns an option to set hash algorithms.
 // algs: name of hash algorithms.
 // Current supported hash algorithms: MD5, SHA-1, SHA-256, SHA-512, CRC-32.
+// Other hash algorithms can be registered by [RegisterHashAlg] or enabled by build tags(e.g. hasher_whirlpool).
 // Call [SupportedHashAlgs] to get supported hash algorithms programmatically.
 // If no hash algorithms specified, it uses [DefaultAlgs].
+// Use [NoneAlg] to count bytes without computing any checksum.
 func Algs(algs []string) Option {
 	return func(c *calculator) {
 		c.algs = algs
@@ -105,6 +213,7 @@ func States(hashed int64, states map[string][]byte) Option {
 type OnHashFunc progress.OnWrittenFunc
 
 // OnHash returns an option to set callback to report progress.
+// For empty input, the callback is called exactly once with current set to 0.
 func OnHash(fn OnHashFunc) Option {
 	return func(c *calculator) {
 		c.fn = fn
@@ -118,10 +227,189 @@ func OnHashInterval(d time.Duration) Option {
 	}
 }
 
+// OnHashEveryTick returns an option to call the callback at every tick of the interval
+// even if no new bytes are calculated.
+// By default, the callback is called only when new bytes are calculated.
+// It's used by UIs to distinguish a stalled or slow source(e.g. "still working, 0 B/s") from a completed one.
+// See [OnHashInterval].
+func OnHashEveryTick() Option {
+	return func(c *calculator) {
+		c.everyTick = true
+	}
+}
+
+// OnSizeExceeded returns an option to set the callback called once when the number of bytes calculated exceeds the total size.
+// It's used to diagnose stale-size bugs(e.g. the total size passed to [Checksums] is smaller than the actual size).
+// The percent reported to the callback set by [OnHash] is always clamped to 100.
+// fn: callback. total: total size. n: number of bytes calculated(including the bytes calculated previously).
+func OnSizeExceeded(fn func(total, n int64)) Option {
+	return func(c *calculator) {
+		c.onSizeExceeded = fn
+	}
+}
+
+// PercentRounder returns an option to set the rounding strategy of the percent passed to the callback.
+// f: function to round the exact percent(e.g. func(p float64) float32 { return float32(math.Floor(p)) }).
+// It's used to keep the percent consistent with the display code of UIs.
+// By default, the percent is the exact percent converted to float32 without rounding.
+// The exact percent passed to f is clamped to 100.
+// See [progress.Percent].
+func PercentRounder(f func(float64) float32) Option {
+	return func(c *calculator) {
+		c.rounder = f
+	}
+}
+
+// percent returns the exact percentage.
+// It follows the same rules as [progress.Percent] but uses float64.
+// All arithmetic stays in int64 and float64, so it is correct for files > 4GB on 32-bit architectures.
+func percent(total, prev, current int64) float64 {
+	if total == 0 {
+		return 100
+	}
+
+	if total < 0 || prev+current < 0 {
+		return 0
+	}
+
+	// Clamp the percent to 100 when the total size is smaller than the actual size(e.g. a stale size).
+	return min(float64(prev+current)/float64(total)*100, 100)
+}
+
+// checkSizeExceeded calls the callback set by [OnSizeExceeded] once if n exceeds the total size.
+func (c *calculator) checkSizeExceeded(total, n int64) {
+	if c.onSizeExceeded != nil && total >= 0 && n > total {
+		c.sizeExceededOnce.Do(func() {
+			c.onSizeExceeded(total, n)
+		})
+	}
+}
+
+// onWritten returns the callback to report progress.
+// It clamps the percent to 100 and applies the percent rounding strategy if it's set.
+// It also logs the progress milestones if the logger is set.
+// It returns nil if neither the callback nor the logger is set.
+func (c *calculator) onWritten() progress.OnWrittenFunc {
+	var fn progress.OnWrittenFunc
+
+	if c.fn != nil {
+		fn = func(total, prev, current int64, pct float32) {
+			if c.rounder != nil {
+				pct = c.rounder(percent(total, prev, current))
+			} else {
+				pct = min(pct, 100)
+			}
+
+			c.checkSizeExceeded(total, prev+current)
+			c.fn(total, prev, current, pct)
+		}
+	}
+
+	if c.logger != nil {
+		fn = c.logMilestones(fn)
+	}
+
+	return fn
+}
+
+// AppendLength returns an option to append the total length of the data to each hash before computing the checksums.
+// It's used for length-committed digests(domain separation) to avoid canonicalization attacks.
+// The framing is: H(data || uint64be(n)),
+// n is the total number of bytes hashed(including the bytes hashed previously when resuming),
+// uint64be(n) is n encoded as a 8-byte big-endian unsigned integer.
+// The length is appended when the calculation is done, so it works even if the total size is unknown.
+// It does not affect the states returned when the calculation is stopped.
+func AppendLength() Option {
+	return func(c *calculator) {
+		c.appendLength = true
+	}
+}
+
+// RateLimit returns an option to limit the rate of reading the data.
+// It's used to calculate checksums in background without saturating the disk or network.
+// bytesPerSec: max number of bytes read per second. No limit if it's <= 0.
+// It uses a token bucket and honors the context cancelation promptly while sleeping.
+func RateLimit(bytesPerSec int64) Option {
+	return func(c *calculator) {
+		c.rateLimit = bytesPerSec
+	}
+}
+
+// ReadBuffering returns an option to buffer the reads of the source.
+// It's used for sources which yield tiny reads(e.g. a decrypting reader returning 16 bytes at a time)
+// to feed the hashes in larger chunks. It's different from the copy buffer.
+// It reads the source until the buffer is full, the source returns an error or the context is canceled.
+// The error of the source is returned after the buffered bytes are calculated.
+// size: size of the read buffer. No buffering if it's <= 0.
+func ReadBuffering(size int) Option {
+	return func(c *calculator) {
+		c.readBufSize = size
+	}
+}
+
+// ExpectedSize returns an option to set the expected total size of the data.
+// It's used to catch truncated downloads(e.g. a server lies about Content-Length)
+// which would otherwise produce a plausible-looking but wrong checksum.
+// n: expected total number of bytes(including the bytes calculated previously when resuming).
+// When the calculation is done, it returns [ErrShortRead] if fewer bytes are read,
+// and [ErrLongRead] if more bytes are read. No checksums are returned in both cases.
+func ExpectedSize(n int64) Option {
+	return func(c *calculator) {
+		c.expectSize = true
+		c.expectedSize = n
+	}
+}
+
+// OnFinalize returns an option to set the callback called right before the checksums are computed(finalized).
+// It's used by UIs to transition from "hashing" to "finalizing" state.
+// fn: callback. algs: sorted hash algorithms to finalize.
+// It's called only when the calculation is done successfully.
+func OnFinalize(fn func(algs []string)) Option {
+	return func(c *calculator) {
+		c.onFinalize = fn
+	}
+}
+
+// SizeEntry returns an option to insert a synthetic entry of the total number of bytes calculated in the checksums map.
+// It's used to serialize a self-describing verification record.
+// The key is [SizeKey]("SIZE") and the value is the number encoded as a 8-byte big-endian unsigned integer.
+// The number includes the bytes calculated previously when resuming.
+// "SIZE" is reserved and never collides with hash algorithms.
+func SizeEntry() Option {
+	return func(c *calculator) {
+		c.sizeEntry = true
+	}
+}
+
+// PartialChecksums returns an option to return the partial checksums along with the error when the calculation fails.
+// It's used to debug flaky custom hashes or readers.
+// By default, no checksums are returned on error.
+// The partial checksums are the digests of bytes read so far, not valid full digests.
+// It does not affect the states returned when the context is canceled or the deadline expires.
+func PartialChecksums() Option {
+	return func(c *calculator) {
+		c.partial = true
+	}
+}
+
+// Prefix returns an option to write the prefix(e.g. salt) into each hash before the data.
+// The checksum is H(prefix || data).
+// It's used for cache keys and namespacing.
+// It's NOT a MAC(e.g. HMAC) and offers no keyed security.
+// The prefix is not counted in the number of bytes calculated or the length appended by [AppendLength].
+// It's not written again when resuming previous calculation, the states already contain it.
+func Prefix(prefix []byte) Option {
+	return func(c *calculator) {
+		c.prefix = prefix
+	}
+}
+
 // ChecksumsBuffer returns the checksums of given hash algorithms by reading r.
 // ctx: [context.Context].
 // It returns states of the hashes instead of checksums
 // if the context is canceled or the deadline expires.
+// The returned error wraps the context error with the number of bytes calculated(including previous ones),
+// use [errors.Is] to check [context.Canceled] or [context.DeadlineExceeded].
 // Users can call [States] to get an option and pass it to the next call of [ChecksumsBuffer],
 // to resume previous calculation.
 // r: read the bytes from r and calculate the hash checksums.
@@ -130,6 +418,9 @@ func OnHashInterval(d time.Duration) Option {
 // Set it to -1 if its total size is unknown.
 // buf: buffer used for the calculation.
 // options: [Option] used to resume previous calculation or report progress.
+// Every byte of r must be seen by the hashes, so the optimized paths of [io.Copy]
+// (e.g. [io.WriterTo], [io.ReaderFrom] and sendfile) are always bypassed.
+// See BenchmarkChecksums for the hashing overhead compared with a pure copy.
 func ChecksumsBuffer(ctx context.Context, r io.Reader, total int64, buf []byte, options ...Option) (written int64, checksums map[string][]byte, err error) {
 	// Set options.
 	c := &calculator{}
@@ -141,21 +432,67 @@ func ChecksumsBuffer(ctx context.Context, r io.Reader, total int64, buf []byte,
 		c.algs = DefaultAlgs
 	}
 
+	// Call the hook when the calculation is done.
+	if onComplete := OnComplete; onComplete != nil {
+		start := nowFunc()
+		defer func() {
+			onComplete(c.algs, written, nowFunc().Sub(start), err)
+		}()
+	}
+
+	// Log the start and the end of the calculation.
+	if c.logger != nil {
+		start := nowFunc()
+		c.logger.LogAttrs(ctx, slog.LevelInfo, "hasher: start",
+			slog.Any("algs", c.algs),
+			slog.Int64("prev", c.hashed),
+			slog.Int64("total", total),
+		)
+
+		defer func() {
+			attrs := []slog.Attr{
+				slog.Any("algs", c.algs),
+				slog.Int64("bytes", written),
+				slog.Duration("duration", nowFunc().Sub(start)),
+			}
+
+			switch {
+			case err == nil:
+				c.logger.LogAttrs(ctx, slog.LevelInfo, "hasher: done", attrs...)
+			case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+				c.logger.LogAttrs(context.Background(), slog.LevelInfo, "hasher: stopped", append(attrs, slog.Any("error", err))...)
+			default:
+				c.logger.LogAttrs(ctx, slog.LevelError, "hasher: error", append(attrs, slog.Any("error", err))...)
+			}
+		}()
+	}
+
 	hashes := make(map[string]hash.Hash)
 	var writers []io.Writer
+	isolated := make(map[string]*isolatedWriter)
 
 	// Create hash.Hash by algorithm
 	for _, alg := range c.algs {
 		// Use upper case letters for algorithm.
 		alg = strings.ToUpper(alg)
 
-		// Get new function for the algorithm.
-		f, ok := hashAlgsToNewFuncs[alg]
-		if !ok {
-			return 0, nil, ErrUnSupportedHashAlg
+		// "NONE" algorithm only counts the bytes.
+		if alg == NoneAlg {
+			continue
 		}
-		// Call f function to new a hash.Hash and insert it to the map.
-		hashes[alg] = f()
+
+		if c.multihashNames {
+			if _, ok := hashAlgsToMultihashNames[alg]; !ok {
+				return 0, nil, ErrNoMultihashName
+			}
+		}
+
+		// New a hash.Hash and insert it to the map.
+		h, err := newHash(alg)
+		if err != nil {
+			return 0, nil, err
+		}
+		hashes[alg] = h
 
 		// Resume previous calculation by loading binary states.
 		if c.hashed > 0 && len(c.states) > 0 {
@@ -172,27 +509,43 @@ func ChecksumsBuffer(ctx context.Context, r io.Reader, total int64, buf []byte,
 			if err = unmarshaler.UnmarshalBinary(state); err != nil {
 				return 0, nil, err
 			}
+		} else if len(c.prefix) != 0 {
+			// Write the prefix before the data.
+			// The states of previous calculation already contain the prefix.
+			h.Write(c.prefix)
 		}
 
-		writers = append(writers, hashes[alg])
+		if c.isolate {
+			isolated[alg] = &isolatedWriter{w: h}
+			writers = append(writers, isolated[alg])
+		} else {
+			writers = append(writers, h)
+		}
 	}
 
 	w := io.MultiWriter(writers...)
 
 	var writer io.Writer = w
 
-	if c.fn != nil {
+	onWritten := c.onWritten()
+
+	if onWritten != nil {
 		// Create a progress.
-		p := progress.New(
-			// Total size.
-			total,
-			// OnWrittenFunc.
-			progress.OnWrittenFunc(c.fn),
-			// Option to set number of bytes copied previously.
-			progress.Prev(c.hashed),
-			// Option to set interval.
-			progress.Interval(c.interval),
-		)
+		var p reporter
+		if c.everyTick {
+			p = newTickProgress(total, c.hashed, onWritten, c.interval)
+		} else {
+			p = progress.New(
+				// Total size.
+				total,
+				// OnWrittenFunc.
+				onWritten,
+				// Option to set number of bytes copied previously.
+				progress.Prev(c.hashed),
+				// Option to set interval.
+				progress.Interval(c.interval),
+			)
+		}
 
 		// Create a multiple writer and dupllicates writes to p.
 		writer = io.MultiWriter(w, p)
@@ -208,15 +561,64 @@ func ChecksumsBuffer(ctx context.Context, r io.Reader, total int64, buf []byte,
 		p.Start(ctx, chExit)
 	}
 
+	// Guard against readers which return (0, nil) repeatedly.
+	r = &noProgressReader{r: r}
+
+	// Abort the calculation if the read rate is too low.
+	copyCtx := ctx
+	if c.minThroughput > 0 && c.throughputWindow > 0 {
+		wr := &watchedReader{r: r}
+		r = wr
+
+		var stop func()
+		copyCtx, stop = startWatchdog(ctx, wr, c.minThroughput, c.throughputWindow)
+		defer stop()
+	}
+
+	if c.readBufSize > 0 {
+		r = newBufferedReader(copyCtx, r, c.readBufSize)
+	}
+
+	if c.rateLimit > 0 {
+		r = newRateLimitedReader(copyCtx, r, c.rateLimit)
+	}
+
 	if len(buf) != 0 {
-		written, err = iocopy.CopyBuffer(ctx, writer, r, buf)
+		written, err = iocopy.CopyBuffer(copyCtx, writer, r, buf)
 	} else {
-		written, err = iocopy.Copy(ctx, writer, r)
+		written, err = iocopy.Copy(copyCtx, writer, r)
+	}
+
+	// Make the error distinct when the watchdog aborts the calculation.
+	if err != nil && errors.Is(context.Cause(copyCtx), ErrThroughputTooLow) {
+		err = fmt.Errorf("%w: %w", ErrThroughputTooLow, err)
+	}
+
+	// Stop the hashes which failed to write.
+	var hashErrs HashWriteErrors
+	for alg, iw := range isolated {
+		if iw.err != nil {
+			if hashErrs == nil {
+				hashErrs = make(HashWriteErrors)
+			}
+			hashErrs[alg] = iw.err
+			delete(hashes, alg)
+		}
 	}
 
 	if err != nil {
-		if err != context.Canceled && err != context.DeadlineExceeded {
-			return written, nil, err
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+			if !c.partial {
+				return written, nil, err
+			}
+
+			// Return the digests of bytes read so far for debugging.
+			partial := make(map[string][]byte)
+			for alg, h := range hashes {
+				partial[c.key(alg)] = h.Sum(nil)
+			}
+
+			return written, partial, err
 		} else {
 			// Calculation stopped.
 			// Return states instead of checksums.
@@ -235,13 +637,58 @@ func ChecksumsBuffer(ctx context.Context, r io.Reader, total int64, buf []byte,
 				states[alg] = state
 			}
 
-			return written, states, err
+			return written, states, fmt.Errorf("hasher: calculation canceled after %d bytes: %w", c.hashed+written, err)
 		}
 	} else {
+		c.checkSizeExceeded(total, c.hashed+written)
+
+		// Check the total size.
+		if c.expectSize {
+			switch n := c.hashed + written; {
+			case n < c.expectedSize:
+				return written, nil, ErrShortRead
+			case n > c.expectedSize:
+				return written, nil, ErrLongRead
+			}
+		}
+
 		checksums = make(map[string][]byte)
 
+		// Progress is reported only when bytes are written.
+		// Report it once for empty input so the callback always receives the final progress.
+		if onWritten != nil && !c.everyTick && written == 0 {
+			onWritten(total, c.hashed, 0, progress.Percent(total, c.hashed, 0))
+		}
+
+		// Append the total length to each hash.
+		var length []byte
+		if c.appendLength {
+			length = binary.BigEndian.AppendUint64(nil, uint64(c.hashed+written))
+		}
+
+		if c.onFinalize != nil {
+			var algs []string
+			for alg := range hashes {
+				algs = append(algs, alg)
+			}
+			sort.Strings(algs)
+
+			c.onFinalize(algs)
+		}
+
 		for alg, h := range hashes {
-			checksums[alg] = h.Sum(nil)
+			if len(length) != 0 {
+				h.Write(length)
+			}
+			checksums[c.key(alg)] = h.Sum(nil)
+		}
+
+		if c.sizeEntry {
+			checksums[SizeKey] = binary.BigEndian.AppendUint64(nil, uint64(c.hashed+written))
+		}
+
+		if hashErrs != nil {
+			return written, checksums, hashErrs
 		}
 
 		return written, checksums, nil
@@ -262,6 +709,28 @@ func Checksums(ctx context.Context, r io.Reader, total int64, options ...Option)
 	return ChecksumsBuffer(ctx, r, total, nil, options...)
 }
 
+// ReadCloserChecksums returns the checksums of given hash algorithms by reading rc,
+// and always closes rc even on error.
+// It's used for sources like HTTP response bodies to prevent descriptor leaks.
+// ctx: [context.Context].
+// It returns states of the hashes instead of checksums
+// if the context is canceled or the deadline expires.
+// See [ChecksumsBuffer].
+// rc: read the bytes from rc and calculate the hash checksums.
+// total: total size of rc. It's used to report the progress.
+// Set it to -1 if its total size is unknown.
+// options: [Option] used to resume previous calculation or report progress.
+// If Close fails, the error is joined with the error of the calculation by [errors.Join].
+func ReadCloserChecksums(ctx context.Context, rc io.ReadCloser, total int64, options ...Option) (written int64, checksums map[string][]byte, err error) {
+	defer func() {
+		if closeErr := rc.Close(); closeErr != nil {
+			err = errors.Join(err, closeErr)
+		}
+	}()
+
+	return Checksums(ctx, rc, total, options...)
+}
+
 // FileChecksumsBuffer reads the file and returns the checksums of given hash algorithms.
 // ctx: [context.Context].
 // It returns states of the hashes instead of checksums
@@ -313,6 +782,159 @@ func FileChecksums(ctx context.Context, filename string, options ...Option) (wri
 	return FileChecksumsBuffer(ctx, filename, nil, options...)
 }
 
+// OpenedFileChecksums reads the opened file from its current offset to EOF and returns the checksums of given hash algorithms.
+// It restores the original offset of the file before returning(even on error),
+// so it does not disturb callers sharing the file handle.
+// ctx: [context.Context].
+// It returns states of the hashes instead of checksums
+// if the context is canceled or the deadline expires.
+// See [ChecksumsBuffer].
+// f: opened file.
+// options: [Option] used to set hash algorithms or report progress.
+func OpenedFileChecksums(ctx context.Context, f *os.File, options ...Option) (written int64, checksums map[string][]byte, err error) {
+	offset, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Restore the original offset.
+	defer func() {
+		if _, seekErr := f.Seek(offset, io.SeekStart); seekErr != nil {
+			err = errors.Join(err, seekErr)
+		}
+	}()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return Checksums(ctx, f, max(fi.Size()-offset, 0), options...)
+}
+
+// FileRangeChecksumsBuffer reads the byte range of the file and returns the checksums of given hash algorithms.
+// It's used to verify a partial download(e.g. HTTP Range) or a file region.
+// ctx: [context.Context].
+// It returns states of the hashes instead of checksums
+// if the context is canceled or the deadline expires.
+// Users can call [States] to get an option and pass it to the next call of [FileRangeChecksumsBuffer],
+// to resume previous calculation.
+// filename: file to calculate the hash checksums.
+// offset: start offset of the range.
+// length: number of bytes of the range. Set it to -1 to read to EOF.
+// It returns [ErrInvalidRange] if offset is beyond EOF or the range exceeds the file size.
+// buf: buffer used for the calculation.
+// options: [Option] used to resume previous calculation or report progress.
+func FileRangeChecksumsBuffer(ctx context.Context, filename string, offset, length int64, buf []byte, options ...Option) (written int64, checksums map[string][]byte, err error) {
+	// Set options.
+	c := &calculator{}
+	for _, option := range options {
+		option(c)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	size := fi.Size()
+
+	if offset < 0 || offset > size {
+		return 0, nil, ErrInvalidRange
+	}
+
+	if length == -1 {
+		length = size - offset
+	}
+
+	// Compare with size-offset to avoid overflow of offset+length.
+	if length < 0 || length > size-offset {
+		return 0, nil, ErrInvalidRange
+	}
+
+	start := offset
+	remaining := length
+
+	// Resume previous calculation by skipping the bytes calculated previously.
+	if c.hashed > 0 && len(c.states) > 0 {
+		if c.hashed > length {
+			return 0, nil, ErrInvalidRange
+		}
+		start += c.hashed
+		remaining -= c.hashed
+	}
+
+	if _, err = f.Seek(start, io.SeekStart); err != nil {
+		return 0, nil, err
+	}
+
+	return ChecksumsBuffer(ctx, io.LimitReader(f, remaining), length, buf, options...)
+}
+
+// FileRangeChecksums reads the byte range of the file and returns the checksums of given hash algorithms.
+// It's used to verify a partial download(e.g. HTTP Range) or a file region.
+// ctx: [context.Context].
+// It returns states of the hashes instead of checksums
+// if the context is canceled or the deadline expires.
+// Users can call [States] to get an option and pass it to the next call of [FileRangeChecksums],
+// to resume previous calculation.
+// filename: file to calculate the hash checksums.
+// offset: start offset of the range.
+// length: number of bytes of the range. Set it to -1 to read to EOF.
+// It returns [ErrInvalidRange] if offset is beyond EOF or the range exceeds the file size.
+// options: [Option] used to resume previous calculation or report progress.
+func FileRangeChecksums(ctx context.Context, filename string, offset, length int64, options ...Option) (written int64, checksums map[string][]byte, err error) {
+	return FileRangeChecksumsBuffer(ctx, filename, offset, length, nil, options...)
+}
+
+// TrailChecksums returns the checksums of the last n bytes of rs.
+// It's used to verify trailers or footers(e.g. zip central directory) without reading the whole stream.
+// ctx: [context.Context].
+// It returns states of the hashes instead of checksums
+// if the context is canceled or the deadline expires.
+// Users can call [States] to get an option and pass it to the next call of [TrailChecksums],
+// to resume previous calculation.
+// rs: [io.ReadSeeker] to read. Its size is got by seeking to the end.
+// n: number of the last bytes to calculate. It's clamped to the size of rs.
+// options: [Option] used to resume previous calculation or report progress.
+func TrailChecksums(ctx context.Context, rs io.ReadSeeker, n int64, options ...Option) (written int64, checksums map[string][]byte, err error) {
+	// Set options.
+	c := &calculator{}
+	for _, option := range options {
+		option(c)
+	}
+
+	size, err := rs.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	n = max(min(n, size), 0)
+	start := size - n
+	remaining := n
+
+	// Resume previous calculation by skipping the bytes calculated previously.
+	if c.hashed > 0 && len(c.states) > 0 {
+		if c.hashed > n {
+			return 0, nil, ErrInvalidRange
+		}
+		start += c.hashed
+		remaining -= c.hashed
+	}
+
+	if _, err = rs.Seek(start, io.SeekStart); err != nil {
+		return 0, nil, err
+	}
+
+	return Checksums(ctx, io.LimitReader(rs, remaining), n, options...)
+}
+
 // URLChecksumsBuffer reads the remote file and returns the checksums of given hash algorithms.
 // ctx: [context.Context].
 // It returns states of the hashes instead of checksums
@@ -368,3 +990,32 @@ func URLChecksumsBuffer(ctx context.Context, url string, buf []byte, options ...
 func URLChecksums(ctx context.Context, url string, options ...Option) (written int64, checksums map[string][]byte, err error) {
 	return URLChecksumsBuffer(ctx, url, nil, options...)
 }
+
+// StdinChecksumsBuffer reads the standard input and returns the checksums of given hash algorithms.
+// It's used to build Unix filter tools(e.g. cat file | mytool).
+// ctx: [context.Context].
+// It returns states of the hashes instead of checksums
+// if the context is canceled or the deadline expires.
+// Users can call [States] to get an option and pass it to the next call of [StdinChecksumsBuffer],
+// to resume previous calculation.
+// The total size of the standard input is unknown,
+// so the percent reported by the callback is always 0.
+// buf: buffer used for the calculation.
+// options: [Option] used to resume previous calculation or report progress.
+func StdinChecksumsBuffer(ctx context.Context, buf []byte, options ...Option) (written int64, checksums map[string][]byte, err error) {
+	return ChecksumsBuffer(ctx, os.Stdin, -1, buf, options...)
+}
+
+// StdinChecksums reads the standard input and returns the checksums of given hash algorithms.
+// It's used to build Unix filter tools(e.g. cat file | mytool).
+// ctx: [context.Context].
+// It returns states of the hashes instead of checksums
+// if the context is canceled or the deadline expires.
+// Users can call [States] to get an option and pass it to the next call of [StdinChecksums],
+// to resume previous calculation.
+// The total size of the standard input is unknown,
+// so the percent reported by the callback is always 0.
+// options: [Option] used to resume previous calculation or report progress.
+func StdinChecksums(ctx context.Context, options ...Option) (written int64, checksums map[string][]byte, err error) {
+	return StdinChecksumsBuffer(ctx, nil, options...)
+}
@@ -1,38 +1,40 @@
 package hasher_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
+	"hash/fnv"
+	"io"
 	"log"
+	"log/slog"
+	"math"
+	"math/rand"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"testing"
 	"time"
 
 	"github.com/northbright/download"
 	"github.com/northbright/hasher"
 )
 
-func ExampleSupportedHashAlgs() {
-	algs := hasher.SupportedHashAlgs()
-	l := len(algs)
-
-	for i, alg := range algs {
-		fmt.Printf("%v: %v", i, alg)
-		if i != l-1 {
-			fmt.Printf("\n")
-		}
-	}
-
-	// Output:
-	// 0: CRC-32
-	// 1: MD5
-	// 2: SHA-1
-	// 3: SHA-256
-	// 4: SHA-512
-}
-
 func ExampleChecksums() {
 	// This example uses hasher.Checksums to read stream from a remote file,
 	// and compute its SHA-256 checksum.
@@ -78,7 +80,7 @@ func ExampleChecksums() {
 	)
 
 	if err != nil {
-		if err != context.Canceled && err != context.DeadlineExceeded {
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
 			log.Printf("hasher.Checksums() error: %v", err)
 			return
 		} else {
@@ -111,7 +113,7 @@ func ExampleChecksums() {
 	)
 
 	if err != nil {
-		if err != context.Canceled && err != context.DeadlineExceeded {
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
 			log.Printf("hasher.Checksums() error: %v", err)
 			return
 		} else {
@@ -180,7 +182,7 @@ func ExampleFileChecksums() {
 	)
 
 	if err != nil {
-		if err != context.Canceled && err != context.DeadlineExceeded {
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
 			log.Printf("hasher.FileChecksums() error: %v", err)
 			return
 		} else {
@@ -210,7 +212,7 @@ func ExampleFileChecksums() {
 	)
 
 	if err != nil {
-		if err != context.Canceled && err != context.DeadlineExceeded {
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
 			log.Printf("hasher.FileChecksums() error: %v", err)
 			return
 		} else {
@@ -253,7 +255,7 @@ func ExampleURLChecksums() {
 	)
 
 	if err != nil {
-		if err != context.Canceled && err != context.DeadlineExceeded {
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
 			log.Printf("hasher.URLChecksums() error: %v", err)
 			return
 		} else {
@@ -283,7 +285,7 @@ func ExampleURLChecksums() {
 	)
 
 	if err != nil {
-		if err != context.Canceled && err != context.DeadlineExceeded {
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
 			log.Printf("hasher.URLChecksums() error: %v", err)
 			return
 		} else {
@@ -297,3 +299,1963 @@ func ExampleURLChecksums() {
 	// Output:
 	// dd9e772686ed908bcff94b6144322d4e2473a7dcd7c696b7e8b6d12f23c887fd
 }
+
+func ExampleAppendLength() {
+	// This example uses hasher.AppendLength to compute a length-committed SHA-256 checksum.
+	// The checksum is SHA-256(data || uint64be(len(data))).
+	r := strings.NewReader("Hello, World!")
+
+	_, checksums, err := hasher.Checksums(
+		// context.Context.
+		context.Background(),
+		// io.Reader.
+		r,
+		// Total size.
+		r.Size(),
+		// Option to set hash algorithms.
+		hasher.Algs([]string{"SHA-256"}),
+		// Option to append the total length to the hashes.
+		hasher.AppendLength(),
+	)
+	if err != nil {
+		log.Printf("hasher.Checksums() error: %v", err)
+		return
+	}
+
+	fmt.Printf("%x", checksums["SHA-256"])
+
+	// Output:
+	// 25f133831ece7df10e19fe457d10a11e6646cf49ce837ac62838147b4324515c
+}
+
+func TestChecksumsEmptyInput(t *testing.T) {
+	expected := map[string]string{
+		"CRC-32":  "00000000",
+		"MD5":     "d41d8cd98f00b204e9800998ecf8427e",
+		"SHA-1":   "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+		"SHA-256": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		"SHA-512": "cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e",
+	}
+
+	calls := 0
+	n, checksums, err := hasher.Checksums(
+		context.Background(),
+		strings.NewReader(""),
+		0,
+		hasher.Algs(hasher.SupportedHashAlgs()),
+		hasher.OnHash(func(total, prev, current int64, percent float32) {
+			calls++
+			if current != 0 || percent != 100 {
+				t.Errorf("OnHash: current = %v, percent = %v, want 0, 100", current, percent)
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("hasher.Checksums() error: %v", err)
+	}
+
+	if n != 0 {
+		t.Errorf("n = %v, want 0", n)
+	}
+
+	if calls != 1 {
+		t.Errorf("OnHash called %v times, want 1", calls)
+	}
+
+	for alg, want := range expected {
+		if got := fmt.Sprintf("%x", checksums[alg]); got != want {
+			t.Errorf("%v: got %v, want %v", alg, got, want)
+		}
+	}
+}
+
+func TestStdinChecksums(t *testing.T) {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error: %v", err)
+	}
+	defer pr.Close()
+
+	stdin := os.Stdin
+	os.Stdin = pr
+	defer func() { os.Stdin = stdin }()
+
+	// Keep the pipe open for a few progress intervals before EOF.
+	go func() {
+		pw.Write([]byte("Hello, World!"))
+		time.Sleep(50 * time.Millisecond)
+		pw.Close()
+	}()
+
+	// The callback is called in the progress goroutine.
+	totals := make(chan int64, 1024)
+
+	n, checksums, err := hasher.StdinChecksums(
+		context.Background(),
+		hasher.Algs([]string{"SHA-256"}),
+		hasher.OnHash(func(total, prev, current int64, percent float32) {
+			select {
+			case totals <- total:
+			default:
+			}
+		}),
+		hasher.OnHashInterval(5*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("hasher.StdinChecksums() error: %v", err)
+	}
+
+	if n != 13 {
+		t.Errorf("n = %d, want 13", n)
+	}
+
+	if got := fmt.Sprintf("%x", checksums["SHA-256"]); got != "dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f" {
+		t.Errorf("got %s", got)
+	}
+
+	if len(totals) == 0 {
+		t.Fatalf("callback not called")
+	}
+
+	for len(totals) > 0 {
+		if total := <-totals; total != -1 {
+			t.Errorf("total = %d, want -1", total)
+		}
+	}
+}
+
+func TestCompareChecksums(t *testing.T) {
+	a := map[string][]byte{
+		"MD5":     {0x01, 0x02},
+		"SHA-1":   {0x03, 0x04},
+		"SHA-256": {0x05, 0x06},
+		"CRC-32":  {0x07, 0x08},
+	}
+
+	b := map[string][]byte{
+		"MD5":     {0x01, 0x02},
+		"SHA-1":   {0x03, 0xff},
+		"SHA-256": {0x05, 0x06},
+		"SHA-512": {0x09, 0x0a},
+	}
+
+	matched, mismatched, onlyInA, onlyInB := hasher.CompareChecksums(a, b)
+
+	for _, c := range []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"matched", matched, []string{"MD5", "SHA-256"}},
+		{"mismatched", mismatched, []string{"SHA-1"}},
+		{"onlyInA", onlyInA, []string{"CRC-32"}},
+		{"onlyInB", onlyInB, []string{"SHA-512"}},
+	} {
+		if fmt.Sprint(c.got) != fmt.Sprint(c.want) {
+			t.Errorf("%v: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	// Disjoint algorithm sets.
+	matched, mismatched, onlyInA, onlyInB = hasher.CompareChecksums(
+		map[string][]byte{"MD5": {0x01}},
+		map[string][]byte{"SHA-1": {0x01}},
+	)
+	if len(matched) != 0 || len(mismatched) != 0 || len(onlyInA) != 1 || len(onlyInB) != 1 {
+		t.Errorf("disjoint: got %v, %v, %v, %v", matched, mismatched, onlyInA, onlyInB)
+	}
+}
+
+func TestOnComplete(t *testing.T) {
+	var (
+		calls  int
+		gotN   int64
+		gotErr error
+	)
+
+	hasher.OnComplete = func(algs []string, n int64, d time.Duration, err error) {
+		calls++
+		gotN = n
+		gotErr = err
+	}
+	defer func() { hasher.OnComplete = nil }()
+
+	n, _, err := hasher.Checksums(context.Background(), strings.NewReader("Hello, World!"), 13)
+	if err != nil {
+		t.Fatalf("hasher.Checksums() error: %v", err)
+	}
+
+	if calls != 1 || gotN != n || gotErr != nil {
+		t.Errorf("OnComplete: calls = %v, n = %v, err = %v, want 1, %v, nil", calls, gotN, gotErr, n)
+	}
+
+	_, _, err = hasher.Checksums(context.Background(), strings.NewReader(""), 0, hasher.Algs([]string{"SHA-3"}))
+	if calls != 2 || gotErr != err || err != hasher.ErrUnSupportedHashAlg {
+		t.Errorf("OnComplete: calls = %v, err = %v, want 2, %v", calls, gotErr, hasher.ErrUnSupportedHashAlg)
+	}
+}
+
+func ExampleTruncateChecksumHex() {
+	// SHA-256 checksum of "Hello, World!".
+	sum, _ := hex.DecodeString("dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f")
+
+	short, err := hasher.TruncateChecksumHex(sum, 4)
+	if err != nil {
+		log.Printf("hasher.TruncateChecksumHex() error: %v", err)
+		return
+	}
+	fmt.Println(short)
+
+	// CRC-32 checksum has only 4 bytes.
+	crc32Sum := []byte{0xec, 0x4a, 0xc3, 0xd0}
+	_, err = hasher.TruncateChecksumHex(crc32Sum, 8)
+	fmt.Println(err)
+
+	// Output:
+	// dffd6021
+	// invalid truncate length
+}
+
+func TestChunkedChecksumsParallel(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 100000)
+	chunkSize := int64(64*1024 + 1)
+
+	leaves, root, err := hasher.ChunkedChecksums(context.Background(), "SHA-256", bytes.NewReader(data), chunkSize)
+	if err != nil {
+		t.Fatalf("hasher.ChunkedChecksums() error: %v", err)
+	}
+
+	for _, concurrency := range []int{0, 1, 3, 100} {
+		leaves2, root2, err := hasher.ChunkedChecksumsParallel(context.Background(), "SHA-256", bytes.NewReader(data), int64(len(data)), chunkSize, concurrency)
+		if err != nil {
+			t.Fatalf("hasher.ChunkedChecksumsParallel() error: %v", err)
+		}
+
+		if len(leaves2) != len(leaves) {
+			t.Fatalf("concurrency %v: got %v leaves, want %v", concurrency, len(leaves2), len(leaves))
+		}
+
+		for i := range leaves {
+			if !bytes.Equal(leaves[i], leaves2[i]) {
+				t.Errorf("concurrency %v: leaf %v mismatch", concurrency, i)
+			}
+		}
+
+		if !bytes.Equal(root, root2) {
+			t.Errorf("concurrency %v: root mismatch", concurrency)
+		}
+	}
+}
+
+func TestChunkedChecksumsParallelLargeChunkSize(t *testing.T) {
+	data := []byte("Hello")
+
+	// size + chunkSize - 1 overflows int64.
+	leaves, root, err := hasher.ChunkedChecksumsParallel(context.Background(), "SHA-256", bytes.NewReader(data), int64(len(data)), math.MaxInt64, 1)
+	if err != nil {
+		t.Fatalf("hasher.ChunkedChecksumsParallel() error: %v", err)
+	}
+
+	sum := sha256.Sum256(data)
+	if len(leaves) != 1 || !bytes.Equal(leaves[0], sum[:]) || !bytes.Equal(root, sum[:]) {
+		t.Errorf("got leaves %x, root %x, want [%x], %x", leaves, root, sum, sum)
+	}
+
+	if _, _, err = hasher.ChunkedChecksumsParallel(context.Background(), "SHA-256", bytes.NewReader(data), -1, 2, 1); !errors.Is(err, hasher.ErrInvalidSize) {
+		t.Errorf("hasher.ChunkedChecksumsParallel() error = %v, want %v", err, hasher.ErrInvalidSize)
+	}
+}
+
+func benchmarkChunkedChecksums(b *testing.B, parallel bool) {
+	data := bytes.Repeat([]byte{0x5a}, 64*1024*1024)
+	chunkSize := int64(1024 * 1024)
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var err error
+		if parallel {
+			_, _, err = hasher.ChunkedChecksumsParallel(context.Background(), "SHA-256", bytes.NewReader(data), int64(len(data)), chunkSize, 0)
+		} else {
+			_, _, err = hasher.ChunkedChecksums(context.Background(), "SHA-256", bytes.NewReader(data), chunkSize)
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkChunkedChecksums(b *testing.B) {
+	benchmarkChunkedChecksums(b, false)
+}
+
+func BenchmarkChunkedChecksumsParallel(b *testing.B) {
+	benchmarkChunkedChecksums(b, true)
+}
+
+func ExampleNoneAlg() {
+	r := strings.NewReader("Hello, World!")
+
+	n, checksums, err := hasher.Checksums(
+		// context.Context.
+		context.Background(),
+		// io.Reader.
+		r,
+		// Total size.
+		r.Size(),
+		// Use "NONE" to count bytes only.
+		hasher.Algs([]string{hasher.NoneAlg}),
+	)
+	if err != nil {
+		log.Printf("hasher.Checksums() error: %v", err)
+		return
+	}
+
+	fmt.Printf("%v bytes, %v checksums", n, len(checksums))
+
+	// Output:
+	// 13 bytes, 0 checksums
+}
+
+func TestOnCompleteDuration(t *testing.T) {
+	// Each call of the fake clock advances one second.
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	restore := hasher.SetNowFunc(func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	})
+	defer restore()
+
+	var got time.Duration
+	hasher.OnComplete = func(algs []string, n int64, d time.Duration, err error) {
+		got = d
+	}
+	defer func() { hasher.OnComplete = nil }()
+
+	if _, _, err := hasher.Checksums(context.Background(), strings.NewReader("Hello, World!"), 13); err != nil {
+		t.Fatalf("hasher.Checksums() error: %v", err)
+	}
+
+	if got != time.Second {
+		t.Errorf("OnComplete: d = %v, want %v", got, time.Second)
+	}
+}
+
+// writeTempFile writes the content to "hello.txt" in a temporary directory and returns the file name.
+func writeTempFile(t *testing.T, content string) string {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), "hello.txt")
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatalf("os.WriteFile() error: %v", err)
+	}
+
+	return filename
+}
+
+func TestFileRangeChecksums(t *testing.T) {
+	filename := writeTempFile(t, "Hello, World!")
+
+	for _, c := range []struct {
+		offset int64
+		length int64
+		n      int64
+		sha256 string
+		err    error
+	}{
+		{7, 5, 5, "78ae647dc5544d227130a0682a51e30bc7777fbb6d8a8f17007463a3ecd1d524", nil},
+		{7, -1, 6, "514b6bb7c846ecfb8d2d29ef0b5c79b63e6ae838f123da936fe827fda654276c", nil},
+		{13, -1, 0, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", nil},
+		{14, -1, 0, "", hasher.ErrInvalidRange},
+		{7, 7, 0, "", hasher.ErrInvalidRange},
+		{-1, 1, 0, "", hasher.ErrInvalidRange},
+		{1, math.MaxInt64, 0, "", hasher.ErrInvalidRange},
+	} {
+		n, checksums, err := hasher.FileRangeChecksums(context.Background(), filename, c.offset, c.length, hasher.Algs([]string{"SHA-256"}))
+		if err != c.err {
+			t.Errorf("offset %v, length %v: err = %v, want %v", c.offset, c.length, err, c.err)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if got := fmt.Sprintf("%x", checksums["SHA-256"]); n != c.n || got != c.sha256 {
+			t.Errorf("offset %v, length %v: got %v, %v, want %v, %v", c.offset, c.length, n, got, c.n, c.sha256)
+		}
+	}
+}
+
+// mapCache implements hasher.Cache by a map.
+type mapCache map[string]hasher.CacheEntry
+
+func (m mapCache) Get(filename string) (hasher.CacheEntry, bool) {
+	entry, ok := m[filename]
+	return entry, ok
+}
+
+func (m mapCache) Put(filename string, entry hasher.CacheEntry) error {
+	m[filename] = entry
+	return nil
+}
+
+func TestCachedFileChecksums(t *testing.T) {
+	filename := writeTempFile(t, "Hello, World!")
+
+	cache := mapCache{}
+
+	for i, c := range []struct {
+		algs       []string
+		recomputed bool
+	}{
+		{[]string{"SHA-256"}, true},
+		{[]string{"sha-256"}, false},
+		// MD5 is not cached.
+		{[]string{"SHA-256", "MD5"}, true},
+		{[]string{"MD5"}, false},
+	} {
+		checksums, recomputed, err := hasher.CachedFileChecksums(context.Background(), filename, cache, hasher.Algs(c.algs))
+		if err != nil {
+			t.Fatalf("%v: hasher.CachedFileChecksums() error: %v", i, err)
+		}
+
+		if recomputed != c.recomputed || len(checksums) != len(c.algs) {
+			t.Errorf("%v: recomputed = %v, %v checksums, want %v, %v", i, recomputed, len(checksums), c.recomputed, len(c.algs))
+		}
+	}
+
+	// Modify the file.
+	if err := os.WriteFile(filename, []byte("Hello, World"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() error: %v", err)
+	}
+
+	_, recomputed, err := hasher.CachedFileChecksums(context.Background(), filename, cache, hasher.Algs([]string{"MD5"}))
+	if err != nil {
+		t.Fatalf("hasher.CachedFileChecksums() error: %v", err)
+	}
+
+	if !recomputed {
+		t.Errorf("recomputed = false after the file is modified")
+	}
+
+	// Options which change the checksums or their keys are rejected.
+	for _, option := range []hasher.Option{
+		hasher.AppendLength(),
+		hasher.Prefix([]byte("salt")),
+		hasher.SizeEntry(),
+		hasher.MultihashNames(),
+	} {
+		if _, _, err := hasher.CachedFileChecksums(context.Background(), filename, cache, option); !errors.Is(err, hasher.ErrNonStandardChecksums) {
+			t.Errorf("hasher.CachedFileChecksums() error = %v, want %v", err, hasher.ErrNonStandardChecksums)
+		}
+	}
+}
+
+func TestRegisterHashAlgErrors(t *testing.T) {
+	for _, c := range []struct {
+		alg string
+		f   func() hash.Hash
+		err error
+	}{
+		{"", sha256.New, hasher.ErrInvalidHashAlg},
+		{"none", sha256.New, hasher.ErrInvalidHashAlg},
+		{"MY-HASH", nil, hasher.ErrInvalidHashAlg},
+		{"sha-256", sha256.New, hasher.ErrHashAlgExists},
+	} {
+		if err := hasher.RegisterHashAlg(c.alg, c.f); err != c.err {
+			t.Errorf("hasher.RegisterHashAlg(%q) error: %v, want %v", c.alg, err, c.err)
+		}
+	}
+}
+
+func ExampleMultihash() {
+	// SHA-256 checksum of "Hello, World!".
+	sum, _ := hex.DecodeString("dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f")
+
+	mh, err := hasher.Multihash("SHA-256", sum)
+	if err != nil {
+		log.Printf("hasher.Multihash() error: %v", err)
+		return
+	}
+
+	fmt.Printf("%x", mh)
+
+	// Output:
+	// 1220dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f
+}
+
+func TestPercentRounder(t *testing.T) {
+	// Get the state after hashing 1 byte.
+	h := sha256.New()
+	h.Write([]byte("a"))
+	state, _ := h.(encoding.BinaryMarshaler).MarshalBinary()
+
+	var got []float32
+
+	// Resume from 1 of 3 bytes with nothing left to read: 33.33...% is floored to 33.
+	_, _, err := hasher.Checksums(
+		context.Background(),
+		strings.NewReader(""),
+		3,
+		hasher.Algs([]string{"SHA-256"}),
+		hasher.States(1, map[string][]byte{"SHA-256": state}),
+		hasher.OnHash(func(total, prev, current int64, percent float32) {
+			got = append(got, percent)
+		}),
+		hasher.PercentRounder(func(p float64) float32 { return float32(math.Floor(p)) }),
+	)
+	if err != nil {
+		t.Fatalf("hasher.Checksums() error: %v", err)
+	}
+
+	if len(got) != 1 || got[0] != 33 {
+		t.Errorf("got %v, want [33]", got)
+	}
+}
+
+func ExampleSelfTest() {
+	if err := hasher.SelfTest(); err != nil {
+		log.Printf("hasher.SelfTest() error: %v", err)
+		return
+	}
+
+	fmt.Println("self test passed")
+
+	// Output:
+	// self test passed
+}
+
+func TestLeadingZeroBits(t *testing.T) {
+	for _, c := range []struct {
+		sum  []byte
+		want int
+	}{
+		{nil, 0},
+		{[]byte{0x80}, 0},
+		{[]byte{0x01}, 7},
+		{[]byte{0x00, 0x00, 0x10}, 19},
+		{[]byte{0x00, 0x00}, 16},
+	} {
+		if got := hasher.LeadingZeroBits(c.sum); got != c.want {
+			t.Errorf("hasher.LeadingZeroBits(%x) = %v, want %v", c.sum, got, c.want)
+		}
+	}
+
+	if !hasher.HasPrefixBits([]byte{0x00, 0x0f}, 12) || hasher.HasPrefixBits([]byte{0x00, 0x0f}, 13) {
+		t.Errorf("hasher.HasPrefixBits() returns wrong result")
+	}
+}
+
+func ExamplePrefix() {
+	// This example uses hasher.Prefix to compute SHA-256("salt:" || "Hello, World!").
+	r := strings.NewReader("Hello, World!")
+
+	_, checksums, err := hasher.Checksums(
+		// context.Context.
+		context.Background(),
+		// io.Reader.
+		r,
+		// Total size.
+		r.Size(),
+		// Option to set hash algorithms.
+		hasher.Algs([]string{"SHA-256"}),
+		// Option to set the prefix.
+		hasher.Prefix([]byte("salt:")),
+	)
+	if err != nil {
+		log.Printf("hasher.Checksums() error: %v", err)
+		return
+	}
+
+	fmt.Printf("%x", checksums["SHA-256"])
+
+	// Output:
+	// 0196a46bb13b01eb2cb43fdd5bc77f57d4a2e086b79ecbfa409fbe7111905f12
+}
+
+// stalledReader sleeps before returning io.EOF to emulate a stalled source.
+type stalledReader struct {
+	d time.Duration
+}
+
+func (r stalledReader) Read(p []byte) (int, error) {
+	time.Sleep(r.d)
+	return 0, io.EOF
+}
+
+// slowReader returns 1 byte for each read after sleeping.
+type slowReader struct {
+	d time.Duration
+}
+
+func (r slowReader) Read(p []byte) (int, error) {
+	time.Sleep(r.d)
+	if len(p) == 0 {
+		return 0, nil
+	}
+	p[0] = 'a'
+	return 1, nil
+}
+
+func TestMinThroughput(t *testing.T) {
+	// About 100 bytes per second, far below the threshold.
+	// It only gets slower on loaded machines.
+	_, states, err := hasher.Checksums(
+		context.Background(),
+		slowReader{d: 10 * time.Millisecond},
+		-1,
+		hasher.Algs([]string{"SHA-256"}),
+		hasher.MinThroughput(10*1024, 100*time.Millisecond),
+	)
+	if !errors.Is(err, hasher.ErrThroughputTooLow) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("hasher.Checksums() error = %v, want %v", err, hasher.ErrThroughputTooLow)
+	}
+
+	if len(states["SHA-256"]) == 0 {
+		t.Errorf("no state of SHA-256 returned")
+	}
+
+	// Small inputs which finish within the window are never aborted.
+	// Use a wide window so that it finishes within the window on loaded machines.
+	_, checksums, err := hasher.Checksums(
+		context.Background(),
+		strings.NewReader("Hello, World!"),
+		13,
+		hasher.Algs([]string{"SHA-256"}),
+		hasher.MinThroughput(1<<40, 10*time.Second),
+	)
+	if err != nil {
+		t.Fatalf("hasher.Checksums() error: %v", err)
+	}
+
+	if got := fmt.Sprintf("%x", checksums["SHA-256"]); got != "dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f" {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestOnHashEveryTick(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+
+	_, _, err := hasher.Checksums(
+		context.Background(),
+		stalledReader{d: time.Millisecond * 300},
+		100,
+		hasher.OnHash(func(total, prev, current int64, percent float32) {
+			mu.Lock()
+			calls++
+			mu.Unlock()
+		}),
+		hasher.OnHashInterval(time.Millisecond*10),
+		hasher.OnHashEveryTick(),
+	)
+	if err != nil {
+		t.Fatalf("hasher.Checksums() error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// About 30 ticks during the stall. Without OnHashEveryTick, it's called only once for the empty input.
+	// Only assert a loose lower bound because ticks may be dropped on loaded machines.
+	if calls < 2 {
+		t.Errorf("OnHash called %v times during the stall, want >= 2", calls)
+	}
+}
+
+func TestS3MultipartETag(t *testing.T) {
+	filename := writeTempFile(t, "Hello, World!")
+
+	for _, c := range []struct {
+		partSize int64
+		want     string
+	}{
+		{5, "69843bf0fe79d671f557442d990e962e-3"},
+		{13, "65a8e27d8879283831b664bd8b7f0ad4"},
+		{1024, "65a8e27d8879283831b664bd8b7f0ad4"},
+	} {
+		etag, err := hasher.S3MultipartETag(context.Background(), filename, c.partSize)
+		if err != nil {
+			t.Fatalf("hasher.S3MultipartETag() error: %v", err)
+		}
+
+		if etag != c.want {
+			t.Errorf("part size %v: got %v, want %v", c.partSize, etag, c.want)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	data := strings.Repeat("a", 1000)
+
+	start := time.Now()
+	n, _, err := hasher.Checksums(context.Background(), strings.NewReader(data), int64(len(data)), hasher.RateLimit(5000))
+	if err != nil {
+		t.Fatalf("hasher.Checksums() error: %v", err)
+	}
+
+	// 1000 bytes at 5000 bytes/s takes about 200ms.
+	// Only assert a loose lower bound because it may take longer on loaded machines.
+	if d := time.Since(start); n != 1000 || d < time.Millisecond*150 {
+		t.Errorf("n = %v, d = %v, want 1000, >= 150ms", n, d)
+	}
+
+	// Context canceled while sleeping.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	start = time.Now()
+	_, _, err = hasher.Checksums(ctx, strings.NewReader(data), int64(len(data)), hasher.RateLimit(100))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// It takes about 10s without the cancelation. Use a wide margin for loaded machines.
+	if d := time.Since(start); d > time.Second*5 {
+		t.Errorf("returned after %v, want < 5s", d)
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+// Progress milestones are logged by the progress goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestLogger(t *testing.T) {
+	var buf syncBuffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	_, _, err := hasher.Checksums(context.Background(), strings.NewReader("Hello, World!"), 13, hasher.Logger(logger))
+	if err != nil {
+		t.Fatalf("hasher.Checksums() error: %v", err)
+	}
+
+	_, _, err = hasher.Checksums(context.Background(), strings.NewReader(""), 0, hasher.Algs([]string{"SHA-3"}), hasher.Logger(logger))
+	if err != hasher.ErrUnSupportedHashAlg {
+		t.Fatalf("err = %v, want %v", err, hasher.ErrUnSupportedHashAlg)
+	}
+
+	for _, want := range []string{
+		`msg="hasher: start"`,
+		`msg="hasher: done" algs="[MD5 SHA-1 SHA-256]" bytes=13`,
+		`level=ERROR msg="hasher: error" algs=[SHA-3] bytes=0`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log does not contain %q:\n%v", want, buf.String())
+		}
+	}
+}
+
+func TestVerifyPieces(t *testing.T) {
+	filename := writeTempFile(t, "Hello, World!")
+
+	// Pieces: "Hello", ", Wor", "ld!"(short piece), and a missing piece.
+	var pieceHashes []byte
+	for _, piece := range []string{"Hello", ", Wor", "ld!", "missing"} {
+		sum := sha1.Sum([]byte(piece))
+		pieceHashes = append(pieceHashes, sum[:]...)
+	}
+	// Corrupt the checksum of the 2nd piece.
+	pieceHashes[sha1.Size] ^= 0xff
+
+	results, err := hasher.VerifyPieces(context.Background(), filename, 5, pieceHashes)
+	if err != nil {
+		t.Fatalf("hasher.VerifyPieces() error: %v", err)
+	}
+
+	if want := []bool{true, false, true, false}; fmt.Sprint(results) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", results, want)
+	}
+
+	if _, err = hasher.VerifyPieces(context.Background(), filename, 5, pieceHashes[:sha1.Size*2]); err != hasher.ErrTooManyPieces {
+		t.Errorf("err = %v, want %v", err, hasher.ErrTooManyPieces)
+	}
+
+	if _, err = hasher.VerifyPieces(context.Background(), filename, 5, pieceHashes[:sha1.Size+1]); err != hasher.ErrInvalidPieceHashes {
+		t.Errorf("err = %v, want %v", err, hasher.ErrInvalidPieceHashes)
+	}
+}
+
+// benchmarkData is the data used by benchmarks to compare hashing with a pure copy.
+var benchmarkData = bytes.Repeat([]byte{0x5a}, 16*1024*1024)
+
+func BenchmarkCopy(b *testing.B) {
+	b.SetBytes(int64(len(benchmarkData)))
+
+	for i := 0; i < b.N; i++ {
+		if _, err := io.Copy(io.Discard, bytes.NewReader(benchmarkData)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkChecksums(b *testing.B) {
+	for _, alg := range []string{hasher.NoneAlg, "CRC-32", "MD5", "SHA-1", "SHA-256", "SHA-512"} {
+		b.Run(alg, func(b *testing.B) {
+			b.SetBytes(int64(len(benchmarkData)))
+
+			for i := 0; i < b.N; i++ {
+				if _, _, err := hasher.Checksums(context.Background(), bytes.NewReader(benchmarkData), int64(len(benchmarkData)), hasher.Algs([]string{alg})); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// tinyReader returns at most 16 bytes for each read.
+type tinyReader struct {
+	r io.Reader
+}
+
+func (r *tinyReader) Read(p []byte) (int, error) {
+	if len(p) > 16 {
+		p = p[:16]
+	}
+	return r.r.Read(p)
+}
+
+func BenchmarkReadBuffering(b *testing.B) {
+	for _, size := range []int{0, 64 * 1024} {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			b.SetBytes(int64(len(benchmarkData)))
+
+			for i := 0; i < b.N; i++ {
+				r := &tinyReader{r: bytes.NewReader(benchmarkData)}
+				if _, _, err := hasher.Checksums(context.Background(), r, int64(len(benchmarkData)), hasher.Algs([]string{"SHA-256"}), hasher.ReadBuffering(size)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestReadBuffering(t *testing.T) {
+	data := bytes.Repeat([]byte("Hello, World!"), 1000)
+
+	_, want, err := hasher.Checksums(context.Background(), bytes.NewReader(data), int64(len(data)), hasher.Algs([]string{"SHA-256"}))
+	if err != nil {
+		t.Fatalf("hasher.Checksums() error: %v", err)
+	}
+
+	_, got, err := hasher.Checksums(context.Background(), &tinyReader{r: bytes.NewReader(data)}, int64(len(data)), hasher.Algs([]string{"SHA-256"}), hasher.ReadBuffering(100))
+	if err != nil {
+		t.Fatalf("hasher.Checksums() error: %v", err)
+	}
+
+	if !bytes.Equal(got["SHA-256"], want["SHA-256"]) {
+		t.Errorf("got %x, want %x", got["SHA-256"], want["SHA-256"])
+	}
+}
+
+// dataErrReader returns the data with the error in a single read, then EOF.
+type dataErrReader struct {
+	data []byte
+	err  error
+	done bool
+}
+
+func (r *dataErrReader) Read(p []byte) (int, error) {
+	if r.done {
+		return 0, io.EOF
+	}
+	r.done = true
+	return copy(p, r.data), r.err
+}
+
+func TestReadBufferingError(t *testing.T) {
+	errX := errors.New("read error")
+
+	_, _, err := hasher.Checksums(context.Background(), &dataErrReader{data: []byte("abc"), err: errX}, -1, hasher.Algs([]string{"SHA-256"}), hasher.ReadBuffering(64))
+	if !errors.Is(err, errX) {
+		t.Errorf("hasher.Checksums() error = %v, want %v", err, errX)
+	}
+}
+
+func TestReadBufferingCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// About 100 bytes per second: filling a 1 MiB buffer takes hours.
+	start := time.Now()
+	_, _, err := hasher.Checksums(ctx, slowReader{d: 10 * time.Millisecond}, -1, hasher.Algs([]string{"SHA-256"}), hasher.ReadBuffering(1024*1024))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("hasher.Checksums() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("canceled after %v", d)
+	}
+}
+
+func ExampleMultihashNames() {
+	_, checksums, err := hasher.Checksums(
+		context.Background(),
+		strings.NewReader("Hello, World!"),
+		13,
+		hasher.Algs([]string{"MD5", "SHA-256"}),
+		hasher.MultihashNames(),
+	)
+	if err != nil {
+		log.Printf("hasher.Checksums() error: %v", err)
+		return
+	}
+
+	for _, name := range []string{"md5", "sha2-256"} {
+		alg, _ := hasher.HashAlgFromMultihashName(name)
+		fmt.Printf("%s(%s): %x\n", name, alg, checksums[name])
+	}
+
+	// Output:
+	// md5(MD5): 65a8e27d8879283831b664bd8b7f0ad4
+	// sha2-256(SHA-256): dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f
+}
+
+func TestMultihashNames(t *testing.T) {
+	for _, alg := range []string{"CRC-32", "MD5", "SHA-1", "SHA-256", "SHA-512"} {
+		name, err := hasher.MultihashName(alg)
+		if err != nil {
+			t.Fatalf("hasher.MultihashName(%q) error: %v", alg, err)
+		}
+
+		got, err := hasher.HashAlgFromMultihashName(name)
+		if err != nil || got != alg {
+			t.Errorf("hasher.HashAlgFromMultihashName(%q) = %q, %v, want %q, nil", name, got, err, alg)
+		}
+	}
+
+	if err := hasher.RegisterHashAlg("FNV-1A-32", func() hash.Hash { return fnv.New32a() }); err != nil {
+		t.Fatalf("hasher.RegisterHashAlg() error: %v", err)
+	}
+	defer hasher.UnregisterHashAlg("FNV-1A-32")
+
+	_, _, err := hasher.Checksums(context.Background(), strings.NewReader("Hello, World!"), 13, hasher.Algs([]string{"FNV-1A-32"}), hasher.MultihashNames())
+	if !errors.Is(err, hasher.ErrNoMultihashName) {
+		t.Errorf("hasher.Checksums() error = %v, want %v", err, hasher.ErrNoMultihashName)
+	}
+}
+
+func ExampleSession() {
+	s, err := hasher.NewSession([]string{"SHA-256"})
+	if err != nil {
+		log.Printf("hasher.NewSession() error: %v", err)
+		return
+	}
+
+	// Feed the messages.
+	s.Write([]byte("Hello, "))
+	s.Write([]byte("World!"))
+
+	// Finalize can be called multiple times.
+	s.Finalize()
+	fmt.Printf("%x\n", s.Finalize()["SHA-256"])
+
+	// Reset the session and feed new data.
+	s.Reset()
+	s.Write([]byte("abc"))
+	fmt.Printf("%x\n", s.Finalize()["SHA-256"])
+
+	// Output:
+	// dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f
+	// ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad
+}
+
+// emptyReader always returns (0, nil).
+type emptyReader struct {
+	calls int
+}
+
+func (r *emptyReader) Read(p []byte) (int, error) {
+	r.calls++
+	return 0, nil
+}
+
+func TestChecksumsNoProgress(t *testing.T) {
+	r := &emptyReader{}
+
+	_, _, err := hasher.Checksums(context.Background(), r, -1)
+	if err != io.ErrNoProgress {
+		t.Errorf("err = %v, want %v", err, io.ErrNoProgress)
+	}
+
+	if r.calls > 1000 {
+		t.Errorf("Read called %v times, want <= 1000", r.calls)
+	}
+}
+
+func TestChecksumsCanceled(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	expired, cancel2 := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel2()
+
+	for _, c := range []struct {
+		ctx    context.Context
+		target error
+	}{
+		{canceled, context.Canceled},
+		{expired, context.DeadlineExceeded},
+	} {
+		_, states, err := hasher.Checksums(c.ctx, strings.NewReader("Hello, World!"), 13, hasher.Algs([]string{"SHA-256"}))
+		if !errors.Is(err, c.target) {
+			t.Errorf("err = %v, want %v", err, c.target)
+		}
+
+		if want := "hasher: calculation canceled after 0 bytes: " + c.target.Error(); err.Error() != want {
+			t.Errorf("err = %q, want %q", err.Error(), want)
+		}
+
+		if len(states["SHA-256"]) == 0 {
+			t.Errorf("no state returned")
+		}
+	}
+}
+
+func TestSplitDigests(t *testing.T) {
+	concatenated := bytes.Repeat([]byte{0x01}, md5.Size*3)
+
+	digests, err := hasher.SplitDigests(concatenated, "md5")
+	if err != nil {
+		t.Fatalf("hasher.SplitDigests() error: %v", err)
+	}
+
+	if len(digests) != 3 || len(digests[2]) != md5.Size {
+		t.Errorf("got %v digests, want 3 digests of %v bytes", len(digests), md5.Size)
+	}
+
+	if _, err = hasher.SplitDigests(concatenated[1:], "MD5"); err != hasher.ErrInvalidDigestsLength {
+		t.Errorf("err = %v, want %v", err, hasher.ErrInvalidDigestsLength)
+	}
+
+	if _, err = hasher.SplitDigests(concatenated, "SHA-3"); err != hasher.ErrUnSupportedHashAlg {
+		t.Errorf("err = %v, want %v", err, hasher.ErrUnSupportedHashAlg)
+	}
+}
+
+// errWrite is returned by failingHash.
+var errWrite = errors.New("write error")
+
+// failingHash is a custom hash which always returns an error on Write.
+type failingHash struct {
+	hash.Hash
+}
+
+func (h failingHash) Write(p []byte) (int, error) {
+	return 0, errWrite
+}
+
+func TestIsolateHashes(t *testing.T) {
+	if err := hasher.RegisterHashAlg("FAILING", func() hash.Hash { return failingHash{sha256.New()} }); err != nil {
+		t.Fatalf("hasher.RegisterHashAlg() error: %v", err)
+	}
+	defer hasher.UnregisterHashAlg("FAILING")
+
+	algs := hasher.Algs([]string{"SHA-256", "FAILING"})
+
+	// By default, the whole calculation fails.
+	_, _, err := hasher.Checksums(context.Background(), strings.NewReader("Hello, World!"), 13, algs)
+	if err != errWrite {
+		t.Errorf("err = %v, want %v", err, errWrite)
+	}
+
+	// Isolate the failing hash.
+	n, checksums, err := hasher.Checksums(context.Background(), strings.NewReader("Hello, World!"), 13, algs, hasher.IsolateHashes())
+
+	var hashErrs hasher.HashWriteErrors
+	if !errors.As(err, &hashErrs) || len(hashErrs) != 1 || hashErrs["FAILING"] != errWrite {
+		t.Fatalf("err = %v, want HashWriteErrors of FAILING", err)
+	}
+
+	if got := fmt.Sprintf("%x", checksums["SHA-256"]); n != 13 || got != "dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f" {
+		t.Errorf("got %v, %v", n, got)
+	}
+
+	if _, ok := checksums["FAILING"]; ok {
+		t.Errorf("checksum of the failing hash is returned")
+	}
+}
+
+func TestMultiReaderChecksumsCounts(t *testing.T) {
+	readers := []io.Reader{
+		strings.NewReader("Hello"),
+		strings.NewReader(""),
+		strings.NewReader(", World!"),
+	}
+
+	n, counts, checksums, err := hasher.MultiReaderChecksumsCounts(context.Background(), readers, 13, hasher.Algs([]string{"SHA-256"}))
+	if err != nil {
+		t.Fatalf("hasher.MultiReaderChecksumsCounts() error: %v", err)
+	}
+
+	if fmt.Sprint(counts) != "[5 0 8]" || n != 13 {
+		t.Errorf("got %v, %v, want [5 0 8], 13", counts, n)
+	}
+
+	if got := fmt.Sprintf("%x", checksums["SHA-256"]); got != "dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f" {
+		t.Errorf("got %v", got)
+	}
+}
+
+func ExampleAlgPreset() {
+	algs, _ := hasher.AlgPreset("web")
+	fmt.Println(algs)
+
+	// Register a custom preset.
+	if err := hasher.RegisterAlgPreset("strong", []string{"sha-512", "sha-256"}); err != nil {
+		log.Printf("hasher.RegisterAlgPreset() error: %v", err)
+		return
+	}
+
+	algs, _ = hasher.AlgPreset("Strong")
+	fmt.Println(algs)
+
+	// Unsupported hash algorithm.
+	err := hasher.RegisterAlgPreset("new", []string{"SHA-3"})
+	fmt.Println(err)
+
+	// Output:
+	// [SHA-256 SHA-1]
+	// [SHA-512 SHA-256]
+	// unsupported hash algorithm
+}
+
+func TestTrailChecksums(t *testing.T) {
+	for _, c := range []struct {
+		n      int64
+		sha256 string
+	}{
+		// "World!".
+		{6, "514b6bb7c846ecfb8d2d29ef0b5c79b63e6ae838f123da936fe827fda654276c"},
+		// Clamped to "Hello, World!".
+		{100, "dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f"},
+	} {
+		_, checksums, err := hasher.TrailChecksums(context.Background(), strings.NewReader("Hello, World!"), c.n, hasher.Algs([]string{"SHA-256"}))
+		if err != nil {
+			t.Fatalf("hasher.TrailChecksums() error: %v", err)
+		}
+
+		if got := fmt.Sprintf("%x", checksums["SHA-256"]); got != c.sha256 {
+			t.Errorf("n = %v: got %v, want %v", c.n, got, c.sha256)
+		}
+	}
+}
+
+func ExampleProgressBarCallback() {
+	var buf bytes.Buffer
+	fn := hasher.ProgressBarCallback(&buf, 8)
+
+	// Emulate the progress reported by the calculation.
+	fn(100, 0, 42, 42)
+	fn(100, 0, 100, 100)
+
+	fmt.Printf("%q\n", buf.String())
+
+	// Unknown total size.
+	buf.Reset()
+	fn = hasher.ProgressBarCallback(&buf, 8)
+	fn(-1, 0, 1024, 0)
+	fn(-1, 0, 2048, 0)
+
+	fmt.Printf("%q\n", buf.String())
+
+	// Output:
+	// "\r[###-----] 42%\r[########] 100%\n"
+	// "\r[|] 1024 bytes\r[/] 2048 bytes"
+}
+
+func TestPercentLargeFile(t *testing.T) {
+	const (
+		gb    = int64(1024 * 1024 * 1024)
+		total = 5 * gb
+		prev  = 3 * gb
+	)
+
+	// Get a state of SHA-256. The bytes hashed previously are emulated and not allocated.
+	h := sha256.New()
+	state, _ := h.(encoding.BinaryMarshaler).MarshalBinary()
+
+	var got32, got64 float32
+	for _, rounder := range []func(float64) float32{nil, func(p float64) float32 { got64 = float32(p); return float32(p) }} {
+		options := []hasher.Option{
+			hasher.Algs([]string{"SHA-256"}),
+			hasher.States(prev, map[string][]byte{"SHA-256": state}),
+			hasher.OnHash(func(total, prev, current int64, percent float32) {
+				got32 = percent
+			}),
+		}
+		if rounder != nil {
+			options = append(options, hasher.PercentRounder(rounder))
+		}
+
+		n, _, err := hasher.Checksums(context.Background(), strings.NewReader(""), total, options...)
+		if err != nil || n != 0 {
+			t.Fatalf("hasher.Checksums() = %v, %v", n, err)
+		}
+
+		if got32 != 60 {
+			t.Errorf("percent = %v, want 60", got32)
+		}
+	}
+
+	if got64 != 60 {
+		t.Errorf("exact percent = %v, want 60", got64)
+	}
+}
+
+func TestExpectedSize(t *testing.T) {
+	for _, c := range []struct {
+		size int64
+		err  error
+	}{
+		{13, nil},
+		{14, hasher.ErrShortRead},
+		{12, hasher.ErrLongRead},
+	} {
+		n, checksums, err := hasher.Checksums(context.Background(), strings.NewReader("Hello, World!"), c.size, hasher.ExpectedSize(c.size))
+		if err != c.err {
+			t.Errorf("expected size %v: err = %v, want %v", c.size, err, c.err)
+		}
+
+		if n != 13 || (err == nil) != (checksums != nil) {
+			t.Errorf("expected size %v: n = %v, checksums = %v", c.size, n, checksums)
+		}
+	}
+}
+
+func ExampleIteratedChecksum() {
+	// SHA-256(SHA-256(SHA-256("abc"))).
+	sum, err := hasher.IteratedChecksum(context.Background(), "SHA-256", []byte("abc"), 3)
+	if err != nil {
+		log.Printf("hasher.IteratedChecksum() error: %v", err)
+		return
+	}
+
+	fmt.Printf("%x\n", sum)
+
+	// Canceled context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = hasher.IteratedChecksum(ctx, "SHA-256", []byte("abc"), 1000000)
+	fmt.Println(err)
+
+	// Output:
+	// f2a778f1a6ed3d5bc59a5d79104c598f3f07093f240ca4e91333fb09ed4f36da
+	// context canceled
+}
+
+func ExampleParseAlgs() {
+	algs, err := hasher.ParseAlgs(" md5, sha-256 ,crc-32")
+	if err != nil {
+		log.Printf("hasher.ParseAlgs() error: %v", err)
+		return
+	}
+	fmt.Println(algs)
+
+	_, err = hasher.ParseAlgs("md5,sha3,sha-1")
+	fmt.Println(err)
+
+	// Output:
+	// [MD5 SHA-256 CRC-32]
+	// unsupported hash algorithm: "sha3"
+}
+
+// closeRecorder records whether Close is called and returns err on Close.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+	err    error
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return r.err
+}
+
+func TestReadCloserChecksums(t *testing.T) {
+	errClose := errors.New("close error")
+
+	for _, c := range []struct {
+		rc  *closeRecorder
+		alg string
+		err []error
+	}{
+		{&closeRecorder{Reader: strings.NewReader("Hello, World!")}, "SHA-256", nil},
+		{&closeRecorder{Reader: strings.NewReader("Hello, World!"), err: errClose}, "SHA-256", []error{errClose}},
+		{&closeRecorder{Reader: strings.NewReader("Hello, World!"), err: errClose}, "SHA-3", []error{errClose, hasher.ErrUnSupportedHashAlg}},
+	} {
+		_, _, err := hasher.ReadCloserChecksums(context.Background(), c.rc, 13, hasher.Algs([]string{c.alg}))
+		if !c.rc.closed {
+			t.Errorf("rc is not closed")
+		}
+
+		if c.err == nil && err != nil {
+			t.Errorf("err = %v, want nil", err)
+		}
+
+		for _, target := range c.err {
+			if !errors.Is(err, target) {
+				t.Errorf("err = %v, want %v", err, target)
+			}
+		}
+	}
+}
+
+func TestVerifyFileAgainstURL(t *testing.T) {
+	filename := writeTempFile(t, "Hello, World!")
+
+	const sha256Hex = "dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f"
+
+	digests := map[string]string{
+		"/bare.sha256":  sha256Hex + "\n",
+		"/sum.sha256":   "0000000000000000000000000000000000000000000000000000000000000000  other.txt\n" + sha256Hex + " *hello.txt\n",
+		"/wrong.sha256": "0000000000000000000000000000000000000000000000000000000000000000  hello.txt\n",
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		digest, ok := digests[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, digest)
+	}))
+	defer ts.Close()
+
+	for path, want := range map[string]bool{
+		"/bare.sha256":  true,
+		"/sum.sha256":   true,
+		"/wrong.sha256": false,
+	} {
+		ok, err := hasher.VerifyFileAgainstURL(context.Background(), filename, "SHA-256", ts.URL+path)
+		if err != nil {
+			t.Fatalf("%v: hasher.VerifyFileAgainstURL() error: %v", path, err)
+		}
+
+		if ok != want {
+			t.Errorf("%v: got %v, want %v", path, ok, want)
+		}
+	}
+
+	if _, err := hasher.VerifyFileAgainstURL(context.Background(), filename, "SHA-256", ts.URL+"/404.sha256"); err == nil {
+		t.Errorf("no error for 404")
+	}
+
+	// Never fall back to the checksum of another file.
+	digests["/others.sha256"] = sha256Hex + "  other.txt\n" + sha256Hex + "\n"
+	if _, err := hasher.VerifyFileAgainstURL(context.Background(), filename, "SHA-256", ts.URL+"/others.sha256"); !errors.Is(err, hasher.ErrNoMatchingChecksum) {
+		t.Errorf("err = %v, want %v", err, hasher.ErrNoMatchingChecksum)
+	}
+}
+
+func TestWriteSidecars(t *testing.T) {
+	filename := writeTempFile(t, "Hello, World!")
+	dir := filepath.Dir(filename)
+
+	// The execute bits are not copied to the sidecar files.
+	if err := os.Chmod(filename, 0750); err != nil {
+		t.Fatalf("os.Chmod() error: %v", err)
+	}
+
+	if _, err := hasher.WriteSidecars(context.Background(), filename, hasher.Algs([]string{"SHA-256", "MD5"})); err != nil {
+		t.Fatalf("hasher.WriteSidecars() error: %v", err)
+	}
+
+	for ext, want := range map[string]string{
+		".sha256": "dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f  hello.txt\n",
+		".md5":    "65a8e27d8879283831b664bd8b7f0ad4  hello.txt\n",
+	} {
+		got, err := os.ReadFile(filename + ext)
+		if err != nil {
+			t.Fatalf("os.ReadFile() error: %v", err)
+		}
+
+		if string(got) != want {
+			t.Errorf("%v: got %q, want %q", ext, got, want)
+		}
+
+		// Same permission bits as the file.
+		fi, err := os.Stat(filename + ext)
+		if err != nil {
+			t.Fatalf("os.Stat() error: %v", err)
+		}
+
+		if perm := fi.Mode().Perm(); perm != 0640 {
+			t.Errorf("%v: got permission %v, want %v", ext, perm, os.FileMode(0640))
+		}
+	}
+
+	// No temporary files left.
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 3 {
+		t.Errorf("got %v files in the directory, want 3", len(entries))
+	}
+
+	// Options which change the checksums or their keys are rejected.
+	for _, option := range []hasher.Option{
+		hasher.AppendLength(),
+		hasher.Prefix([]byte("salt")),
+		hasher.SizeEntry(),
+		hasher.MultihashNames(),
+	} {
+		if _, err := hasher.WriteSidecars(context.Background(), filename, hasher.Algs([]string{"SHA-256"}), option); !errors.Is(err, hasher.ErrNonStandardChecksums) {
+			t.Errorf("hasher.WriteSidecars() error = %v, want %v", err, hasher.ErrNonStandardChecksums)
+		}
+	}
+
+	// No sidecar file for "NONE".
+	if _, err := hasher.WriteSidecars(context.Background(), filename, hasher.Algs([]string{"SHA-256", hasher.NoneAlg})); err != nil {
+		t.Fatalf("hasher.WriteSidecars() error: %v", err)
+	}
+
+	if entries, _ = os.ReadDir(dir); len(entries) != 3 {
+		t.Errorf("got %v files in the directory, want 3", len(entries))
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	dir := t.TempDir()
+
+	var paths []string
+	for i, content := range []string{"a", "b", "a", "c", "b", "a"} {
+		path := filepath.Join(dir, fmt.Sprintf("%v.txt", i))
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("os.WriteFile() error: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	groups, err := hasher.FindDuplicates(context.Background(), "SHA-256", paths, 2)
+	if err != nil {
+		t.Fatalf("hasher.FindDuplicates() error: %v", err)
+	}
+
+	if len(groups) != 2 {
+		t.Fatalf("got %v groups, want 2", len(groups))
+	}
+
+	sumA := fmt.Sprintf("%x", sha256.Sum256([]byte("a")))
+	if want := []string{paths[0], paths[2], paths[5]}; fmt.Sprint(groups[sumA]) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", groups[sumA], want)
+	}
+
+	// Missing file.
+	if _, err = hasher.FindDuplicates(context.Background(), "SHA-256", append(paths, filepath.Join(dir, "missing")), 2); err == nil {
+		t.Errorf("no error for missing file")
+	}
+
+	// Canceled context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err = hasher.FindDuplicates(ctx, "SHA-256", paths, 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want %v", err, context.Canceled)
+	}
+}
+
+// errReader always returns err.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
+func TestStreamsEqual(t *testing.T) {
+	errRead := errors.New("read error")
+
+	for _, c := range []struct {
+		a, b  io.Reader
+		equal bool
+		err   error
+	}{
+		{strings.NewReader("Hello, World!"), strings.NewReader("Hello, World!"), true, nil},
+		{strings.NewReader("Hello, World!"), strings.NewReader("Hello, World"), false, nil},
+		// The second stream is slow and hashing it is canceled by the failure of the first one.
+		{errReader{errRead}, stalledReader{d: time.Millisecond * 100}, false, errRead},
+	} {
+		equal, err := hasher.StreamsEqual(context.Background(), "SHA-256", c.a, c.b)
+		if err != c.err || equal != c.equal {
+			t.Errorf("got %v, %v, want %v, %v", equal, err, c.equal, c.err)
+		}
+	}
+}
+
+func ExampleOnFinalize() {
+	r := strings.NewReader("Hello, World!")
+
+	_, _, err := hasher.Checksums(
+		// context.Context.
+		context.Background(),
+		// io.Reader.
+		r,
+		// Total size.
+		r.Size(),
+		// Option to set hash algorithms.
+		hasher.Algs([]string{"SHA-256", "MD5"}),
+		// Option to set the callback called before finalizing.
+		hasher.OnFinalize(func(algs []string) {
+			fmt.Printf("finalizing %v\n", algs)
+		}),
+	)
+	if err != nil {
+		log.Printf("hasher.Checksums() error: %v", err)
+		return
+	}
+
+	// Output:
+	// finalizing [MD5 SHA-256]
+}
+
+func ExampleSession_Snapshot() {
+	s, err := hasher.NewSession([]string{"SHA-256"})
+	if err != nil {
+		log.Printf("hasher.NewSession() error: %v", err)
+		return
+	}
+
+	s.Write([]byte("Hello, "))
+
+	// Take a snapshot as a checkpoint.
+	states, n, err := s.Snapshot()
+	if err != nil {
+		log.Printf("Snapshot() error: %v", err)
+		return
+	}
+
+	// Resume the calculation from the checkpoint.
+	_, checksums, err := hasher.Checksums(
+		context.Background(),
+		strings.NewReader("World!"),
+		13,
+		hasher.Algs([]string{"SHA-256"}),
+		hasher.States(n, states),
+	)
+	if err != nil {
+		log.Printf("hasher.Checksums() error: %v", err)
+		return
+	}
+
+	fmt.Printf("%v\n%x", n, checksums["SHA-256"])
+
+	// Output:
+	// 7
+	// dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f
+}
+
+func ExampleBestChecksum() {
+	alg, sum, err := hasher.BestChecksum(context.Background(), []string{"md5", "sha-256", "sha3", "sha-1"}, strings.NewReader("Hello, World!"))
+	if err != nil {
+		log.Printf("hasher.BestChecksum() error: %v", err)
+		return
+	}
+
+	fmt.Printf("%v: %x\n", alg, sum)
+
+	_, _, err = hasher.BestChecksum(context.Background(), []string{"sha3"}, strings.NewReader("Hello, World!"))
+	fmt.Println(err)
+
+	// Output:
+	// SHA-256: dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f
+	// unsupported hash algorithm
+}
+
+func TestStrongestAlg(t *testing.T) {
+	// WHIRLPOOL is enabled by the hasher_whirlpool build tag.
+	// Register a stand-in without the tag.
+	if err := hasher.RegisterHashAlg("WHIRLPOOL", sha512.New); err == nil {
+		defer hasher.UnregisterHashAlg("WHIRLPOOL")
+	}
+
+	if err := hasher.RegisterHashAlg("FNV-1A-32", func() hash.Hash { return fnv.New32a() }); err != nil {
+		t.Fatalf("hasher.RegisterHashAlg() error: %v", err)
+	}
+	defer hasher.UnregisterHashAlg("FNV-1A-32")
+
+	for _, c := range []struct {
+		available []string
+		want      string
+	}{
+		{[]string{"CRC-32", "WHIRLPOOL"}, "WHIRLPOOL"},
+		{[]string{"whirlpool", "SHA-256", "SHA-512"}, "SHA-512"},
+		{[]string{"SHA-256", "WHIRLPOOL", "MD5"}, "WHIRLPOOL"},
+		{[]string{"FNV-1A-32", "CRC-32"}, "CRC-32"},
+		{[]string{"FNV-1A-32", "NONE"}, "FNV-1A-32"},
+	} {
+		got, ok := hasher.StrongestAlg(c.available)
+		if !ok || got != c.want {
+			t.Errorf("hasher.StrongestAlg(%v) = %q, %v, want %q, true", c.available, got, ok, c.want)
+		}
+	}
+}
+
+// readAtRecorder records the offsets and lengths of ReadAt calls.
+type readAtRecorder struct {
+	io.ReaderAt
+	calls []string
+}
+
+func (r *readAtRecorder) ReadAt(p []byte, off int64) (int, error) {
+	r.calls = append(r.calls, fmt.Sprintf("%v:%v", off, len(p)))
+	return r.ReaderAt.ReadAt(p, off)
+}
+
+func TestReaderAtChecksums(t *testing.T) {
+	ra := &readAtRecorder{ReaderAt: strings.NewReader("Hello, World!")}
+
+	n, checksums, err := hasher.ReaderAtChecksums(context.Background(), ra, 13, 4, hasher.Algs([]string{"SHA-256"}))
+	if err != nil {
+		t.Fatalf("hasher.ReaderAtChecksums() error: %v", err)
+	}
+
+	if got := fmt.Sprintf("%x", checksums["SHA-256"]); n != 13 || got != "dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f" {
+		t.Errorf("got %v, %v", n, got)
+	}
+
+	if want := "[0:4 4:4 8:4 12:1]"; fmt.Sprint(ra.calls) != want {
+		t.Errorf("ReadAt calls: got %v, want %v", ra.calls, want)
+	}
+
+	if _, _, err = hasher.ReaderAtChecksums(context.Background(), ra, 13, 0); err != hasher.ErrInvalidAlignSize {
+		t.Errorf("err = %v, want %v", err, hasher.ErrInvalidAlignSize)
+	}
+
+	if _, _, err = hasher.ReaderAtChecksums(context.Background(), ra, -1, 4); err != hasher.ErrInvalidSize {
+		t.Errorf("err = %v, want %v", err, hasher.ErrInvalidSize)
+	}
+
+	// Size is larger than the data.
+	if _, _, err = hasher.ReaderAtChecksums(context.Background(), strings.NewReader("Hello"), 13, 4); err != io.ErrUnexpectedEOF {
+		t.Errorf("err = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func ExampleMigrationAdvice() {
+	for _, alg := range []string{"MD5", "SHA-1", "CRC-32", "SHA-256", "SHA-512", "SHA-3"} {
+		supported, replacement := hasher.MigrationAdvice(alg)
+		fmt.Printf("%v: supported: %v, replacement: %q\n", alg, supported, replacement)
+	}
+
+	// Output:
+	// MD5: supported: true, replacement: "SHA-256"
+	// SHA-1: supported: true, replacement: "SHA-256"
+	// CRC-32: supported: true, replacement: "SHA-256"
+	// SHA-256: supported: true, replacement: ""
+	// SHA-512: supported: true, replacement: ""
+	// SHA-3: supported: false, replacement: "SHA-256"
+}
+
+func ExampleSizeEntry() {
+	r := strings.NewReader("Hello, World!")
+
+	_, checksums, err := hasher.Checksums(
+		// context.Context.
+		context.Background(),
+		// io.Reader.
+		r,
+		// Total size.
+		r.Size(),
+		// Option to set hash algorithms.
+		hasher.Algs([]string{"SHA-256"}),
+		// Option to insert the size entry.
+		hasher.SizeEntry(),
+	)
+	if err != nil {
+		log.Printf("hasher.Checksums() error: %v", err)
+		return
+	}
+
+	fmt.Printf("%v bytes\n", binary.BigEndian.Uint64(checksums[hasher.SizeKey]))
+	fmt.Println(hasher.RegisterHashAlg(hasher.SizeKey, sha256.New))
+
+	// Output:
+	// 13 bytes
+	// invalid hash algorithm
+}
+
+func TestOpenedFileChecksums(t *testing.T) {
+	filename := writeTempFile(t, "Hello, World!")
+
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatalf("os.Open() error: %v", err)
+	}
+	defer f.Close()
+
+	if _, err = f.Seek(7, io.SeekStart); err != nil {
+		t.Fatalf("Seek() error: %v", err)
+	}
+
+	n, checksums, err := hasher.OpenedFileChecksums(context.Background(), f, hasher.Algs([]string{"SHA-256"}))
+	if err != nil {
+		t.Fatalf("hasher.OpenedFileChecksums() error: %v", err)
+	}
+
+	// "World!".
+	if got := fmt.Sprintf("%x", checksums["SHA-256"]); n != 6 || got != "514b6bb7c846ecfb8d2d29ef0b5c79b63e6ae838f123da936fe827fda654276c" {
+		t.Errorf("got %v, %v", n, got)
+	}
+
+	if offset, _ := f.Seek(0, io.SeekCurrent); offset != 7 {
+		t.Errorf("offset = %v, want 7", offset)
+	}
+}
+
+func ExampleChecksumsAndVerify() {
+	_, matches, err := hasher.ChecksumsAndVerify(
+		context.Background(),
+		strings.NewReader("Hello, World!"),
+		map[string]string{
+			"sha-256": "dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f",
+			"md5":     "00000000000000000000000000000000",
+		},
+	)
+	if err != nil {
+		log.Printf("hasher.ChecksumsAndVerify() error: %v", err)
+		return
+	}
+
+	fmt.Printf("MD5: %v, SHA-256: %v", matches["MD5"], matches["SHA-256"])
+
+	// Output:
+	// MD5: false, SHA-256: true
+}
+
+func TestDecompressedChecksums(t *testing.T) {
+	const sha256Hex = "dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f"
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte("Hello, World!"))
+	zw.Close()
+
+	// bzip2 compressed "Hello, World!".
+	bz2, _ := hex.DecodeString("425a6839314159265359e6d8fedf0000019780600400400080060490002000220323210030b2805ade43ef177245385090e6d8fedf")
+
+	for name, r := range map[string]io.Reader{
+		"gzip":  &gz,
+		"bzip2": bytes.NewReader(bz2),
+		"plain": strings.NewReader("Hello, World!"),
+	} {
+		n, checksums, err := hasher.DecompressedChecksums(context.Background(), r, hasher.Algs([]string{"SHA-256"}))
+		if err != nil {
+			t.Fatalf("%v: hasher.DecompressedChecksums() error: %v", name, err)
+		}
+
+		if got := fmt.Sprintf("%x", checksums["SHA-256"]); n != 13 || got != sha256Hex {
+			t.Errorf("%v: got %v, %v", name, n, got)
+		}
+	}
+
+	// Short input.
+	n, _, err := hasher.DecompressedChecksums(context.Background(), strings.NewReader("B"))
+	if err != nil || n != 1 {
+		t.Errorf("short input: got %v, %v", n, err)
+	}
+}
+
+func TestPartialChecksums(t *testing.T) {
+	errRead := errors.New("read error")
+	newReader := func() io.Reader {
+		return io.MultiReader(strings.NewReader("Hello"), errReader{errRead})
+	}
+
+	// By default, no checksums are returned on error.
+	_, checksums, err := hasher.Checksums(context.Background(), newReader(), -1, hasher.Algs([]string{"SHA-256"}))
+	if err != errRead || checksums != nil {
+		t.Errorf("got %v, %v, want nil, %v", checksums, err, errRead)
+	}
+
+	n, checksums, err := hasher.Checksums(context.Background(), newReader(), -1, hasher.Algs([]string{"SHA-256"}), hasher.PartialChecksums())
+	if err != errRead {
+		t.Errorf("err = %v, want %v", err, errRead)
+	}
+
+	// SHA-256 of "Hello".
+	want := "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969"
+	if got := fmt.Sprintf("%x", checksums["SHA-256"]); n != 5 || got != want {
+		t.Errorf("got %v, %v, want 5, %v", n, got, want)
+	}
+}
+
+func TestSupportedHashAlgsOrder(t *testing.T) {
+	custom := []string{"ZZ-HASH", "AA-HASH", "MD4", "sha-384", "b-hash"}
+
+	// Hash algorithms enabled by build tags are included.
+	algs := hasher.SupportedHashAlgs()
+	for _, alg := range custom {
+		algs = append(algs, strings.ToUpper(alg))
+	}
+	sort.Strings(algs)
+	want := fmt.Sprint(algs)
+
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10; i++ {
+		rnd.Shuffle(len(custom), func(i, j int) { custom[i], custom[j] = custom[j], custom[i] })
+
+		for _, alg := range custom {
+			if err := hasher.RegisterHashAlg(alg, sha256.New); err != nil {
+				t.Fatalf("hasher.RegisterHashAlg(%v) error: %v", alg, err)
+			}
+		}
+
+		got := fmt.Sprint(hasher.SupportedHashAlgs())
+
+		for _, alg := range custom {
+			hasher.UnregisterHashAlg(strings.ToUpper(alg))
+		}
+
+		if got != want {
+			t.Fatalf("registration order %v: got %v, want %v", custom, got, want)
+		}
+	}
+}
+
+func TestSimilarityScore(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	a := make([]byte, 1000)
+	rnd.Read(a)
+
+	// Insert bytes at the head and modify the 6th block of a.
+	b := append([]byte("inserted"), a...)
+	b[8+550] ^= 0xff
+
+	other := make([]byte, 1000)
+	rnd.Read(other)
+
+	for _, c := range []struct {
+		name string
+		b    []byte
+		want float64
+	}{
+		{"identical", a, 1},
+		{"modified", b, 0.9},
+		{"different", other, 0},
+	} {
+		score, err := hasher.SimilarityScore(context.Background(), bytes.NewReader(a), bytes.NewReader(c.b), 100)
+		if err != nil {
+			t.Fatalf("%v: hasher.SimilarityScore() error: %v", c.name, err)
+		}
+
+		if score != c.want {
+			t.Errorf("%v: score = %v, want %v", c.name, score, c.want)
+		}
+	}
+
+	if _, err := hasher.SimilarityScore(context.Background(), bytes.NewReader(a), bytes.NewReader(a), 0); err != hasher.ErrInvalidWindowSize {
+		t.Errorf("err = %v, want %v", err, hasher.ErrInvalidWindowSize)
+	}
+}
+
+func ExampleLoadCheckpoint() {
+	f, err := os.CreateTemp("", "hello-*.txt")
+	if err != nil {
+		log.Printf("os.CreateTemp() error: %v", err)
+		return
+	}
+	filename := f.Name()
+	defer os.Remove(filename)
+
+	_, err = f.WriteString("Hello, World!")
+	f.Close()
+	if err != nil {
+		log.Printf("f.WriteString() error: %v", err)
+		return
+	}
+
+	// Emulate a calculation stopped after 7 bytes.
+	s, _ := hasher.NewSession([]string{"SHA-256"})
+	s.Write([]byte("Hello, "))
+	states, n, _ := s.Snapshot()
+
+	// Save the checkpoint to disk(a buffer here).
+	var buf bytes.Buffer
+	if err := hasher.SaveCheckpoint(&buf, &hasher.Checkpoint{Source: filename, Hashed: n, States: states}); err != nil {
+		log.Printf("hasher.SaveCheckpoint() error: %v", err)
+		return
+	}
+
+	// Load the checkpoint in a later process and resume the calculation.
+	cp, err := hasher.LoadCheckpoint(&buf)
+	if err != nil {
+		log.Printf("hasher.LoadCheckpoint() error: %v", err)
+		return
+	}
+
+	_, checksums, err := hasher.FileChecksums(context.Background(), cp.Source, cp.Options()...)
+	if err != nil {
+		log.Printf("hasher.FileChecksums() error: %v", err)
+		return
+	}
+
+	fmt.Printf("%x", checksums["SHA-256"])
+
+	// Output:
+	// dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f
+}
+
+func TestOnSizeExceeded(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		percents []float32
+		calls    int
+		gotN     int64
+	)
+
+	// The declared total size(5) is smaller than the actual size(13).
+	_, _, err := hasher.Checksums(
+		context.Background(),
+		strings.NewReader("Hello, World!"),
+		5,
+		hasher.Algs([]string{"SHA-256"}),
+		hasher.OnHash(func(total, prev, current int64, percent float32) {
+			mu.Lock()
+			defer mu.Unlock()
+			percents = append(percents, percent)
+		}),
+		hasher.OnSizeExceeded(func(total, n int64) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			gotN = n
+		}),
+	)
+	if err != nil {
+		t.Fatalf("hasher.Checksums() error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, p := range percents {
+		if p > 100 {
+			t.Errorf("percent = %v, want <= 100", p)
+		}
+	}
+
+	if calls != 1 || gotN != 13 {
+		t.Errorf("OnSizeExceeded callback called %d times with n = %d, want 1 time with n = 13", calls, gotN)
+	}
+}
@@ -0,0 +1,61 @@
+package hasher
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// HashWriteErrors records the errors returned by Write of the hashes when [IsolateHashes] is set.
+// key: algorithm, value: the first error returned by Write of the hash.
+type HashWriteErrors map[string]error
+
+// Error implements the error interface.
+func (e HashWriteErrors) Error() string {
+	var algs []string
+	for alg := range e {
+		algs = append(algs, alg)
+	}
+	sort.Strings(algs)
+
+	var msgs []string
+	for _, alg := range algs {
+		msgs = append(msgs, fmt.Sprintf("%v: %v", alg, e[alg]))
+	}
+
+	return "hash write errors: " + strings.Join(msgs, "; ")
+}
+
+// IsolateHashes returns an option to isolate each hash from the others.
+// By default, all hashes share one [io.MultiWriter],
+// so if the Write of one hash returns an error, the whole calculation fails.
+// When it's set, a hash which fails to write is stopped and the others continue.
+// When the calculation is done, the checksums of the successful hashes are returned with a [HashWriteErrors] error.
+// The hashes of the standard library never return an error on Write,
+// it's only useful for custom hashes registered by [RegisterHashAlg].
+func IsolateHashes() Option {
+	return func(c *calculator) {
+		c.isolate = true
+	}
+}
+
+// isolatedWriter records the first error of the writer and always reports success,
+// so that it does not abort the [io.MultiWriter].
+type isolatedWriter struct {
+	w   io.Writer
+	err error
+}
+
+// Write implements [io.Writer] interface.
+func (w *isolatedWriter) Write(p []byte) (n int, err error) {
+	if w.err == nil {
+		if n, err = w.w.Write(p); err != nil {
+			w.err = err
+		} else if n != len(p) {
+			w.err = io.ErrShortWrite
+		}
+	}
+
+	return len(p), nil
+}
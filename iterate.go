@@ -0,0 +1,49 @@
+package hasher
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrInvalidIterations indicates that the number of iterations is invalid.
+	ErrInvalidIterations = errors.New("invalid iterations")
+)
+
+// iteratedCheckInterval is the number of iterations between context checks.
+const iteratedCheckInterval = 1024
+
+// IteratedChecksum returns the iterated checksum of data: H(H(...H(data))).
+// The hash is applied iterations times.
+// It's used by some protocol-specific derivations.
+// It's NOT a secure KDF.
+// Use a real one(e.g. Argon2, scrypt or PBKDF2 in golang.org/x/crypto) for passwords.
+// ctx: [context.Context]. It's checked periodically during long iteration counts.
+// alg: hash algorithm.
+// data: data to hash.
+// iterations: number of times to apply the hash. It should be >= 1.
+func IteratedChecksum(ctx context.Context, alg string, data []byte, iterations int) ([]byte, error) {
+	if iterations < 1 {
+		return nil, ErrInvalidIterations
+	}
+
+	h, err := newHash(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := data
+	for i := 0; i < iterations; i++ {
+		if i%iteratedCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		h.Reset()
+		h.Write(sum)
+		sum = h.Sum(nil)
+	}
+
+	return sum, nil
+}
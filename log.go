@@ -0,0 +1,42 @@
+package hasher
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/northbright/iocopy/progress"
+)
+
+// Logger returns an option to set the logger to log structured records.
+// When it's set, the calculation logs records at start, progress milestones(every 25%), completion and error.
+// Records have attributes like "algs", "bytes", "total" and "duration".
+// When it's not set, no logging occurs.
+func Logger(logger *slog.Logger) Option {
+	return func(c *calculator) {
+		c.logger = logger
+	}
+}
+
+// logMilestones returns a callback which logs the progress milestones(every 25%) and then calls fn if it's not nil.
+// Milestones are logged only when the total size is known.
+func (c *calculator) logMilestones(fn progress.OnWrittenFunc) progress.OnWrittenFunc {
+	milestone := 0
+
+	return func(total, prev, current int64, pct float32) {
+		if total > 0 {
+			if m := int(percent(total, prev, current)) / 25; m > milestone {
+				milestone = m
+				c.logger.LogAttrs(context.Background(), slog.LevelInfo, "hasher: progress",
+					slog.Any("algs", c.algs),
+					slog.Int64("bytes", prev+current),
+					slog.Int64("total", total),
+					slog.Int("percent", m*25),
+				)
+			}
+		}
+
+		if fn != nil {
+			fn(total, prev, current, pct)
+		}
+	}
+}
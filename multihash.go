@@ -0,0 +1,96 @@
+package hasher
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+var (
+	// hashAlgsToMultihashCodes maps hash algorithms to the multihash codes.
+	// See https://github.com/multiformats/multicodec/blob/master/table.csv
+	hashAlgsToMultihashCodes = map[string]uint64{
+		"MD5":     0xd5,
+		"SHA-1":   0x11,
+		"SHA-256": 0x12,
+		"SHA-512": 0x13,
+		"CRC-32":  0x0132,
+	}
+
+	// ErrNoMultihashCode indicates that the hash algorithm has no registered multihash code.
+	ErrNoMultihashCode = errors.New("no multihash code")
+)
+
+// Multihash returns the multihash format of the checksum.
+// It's used for content-addressing ecosystems(e.g. IPFS and libp2p).
+// The format is: varint(code) || varint(len(sum)) || sum.
+// alg: hash algorithm of the checksum.
+// sum: checksum.
+// It returns [ErrNoMultihashCode] if the hash algorithm has no registered multihash code.
+func Multihash(alg string, sum []byte) ([]byte, error) {
+	code, ok := hashAlgsToMultihashCodes[strings.ToUpper(alg)]
+	if !ok {
+		return nil, ErrNoMultihashCode
+	}
+
+	buf := binary.AppendUvarint(nil, code)
+	buf = binary.AppendUvarint(buf, uint64(len(sum)))
+	return append(buf, sum...), nil
+}
+
+var (
+	// hashAlgsToMultihashNames maps hash algorithms to the multicodec names.
+	// See https://github.com/multiformats/multicodec/blob/master/table.csv
+	hashAlgsToMultihashNames = map[string]string{
+		"MD5":     "md5",
+		"SHA-1":   "sha1",
+		"SHA-256": "sha2-256",
+		"SHA-512": "sha2-512",
+		"CRC-32":  "crc32",
+	}
+
+	// ErrNoMultihashName indicates that the hash algorithm or the multicodec name has no mapping.
+	ErrNoMultihashName = errors.New("no multihash name")
+)
+
+// MultihashName returns the multicodec name of the hash algorithm(e.g. "sha2-256" for "SHA-256").
+// It returns [ErrNoMultihashName] if the hash algorithm has no multicodec name.
+func MultihashName(alg string) (string, error) {
+	name, ok := hashAlgsToMultihashNames[strings.ToUpper(alg)]
+	if !ok {
+		return "", ErrNoMultihashName
+	}
+	return name, nil
+}
+
+// HashAlgFromMultihashName returns the hash algorithm of the multicodec name(e.g. "SHA-256" for "sha2-256").
+// It's the reverse of [MultihashName].
+// It returns [ErrNoMultihashName] if no hash algorithm has the multicodec name.
+func HashAlgFromMultihashName(name string) (string, error) {
+	name = strings.ToLower(name)
+	for alg, n := range hashAlgsToMultihashNames {
+		if n == name {
+			return alg, nil
+		}
+	}
+	return "", ErrNoMultihashName
+}
+
+// MultihashNames returns an option to use the multicodec names(e.g. "sha2-256") as the keys of the checksums.
+// It's used by cross-ecosystem tooling(e.g. IPFS and IPLD). Call [HashAlgFromMultihashName] to get the hash algorithm back.
+// The calculation fails with [ErrNoMultihashName] if any hash algorithm has no multicodec name.
+// The keys of the states returned when the calculation is stopped are not changed,
+// so they can be passed to [States] to resume the calculation.
+func MultihashNames() Option {
+	return func(c *calculator) {
+		c.multihashNames = true
+	}
+}
+
+// key returns the key of the checksum for the hash algorithm.
+func (c *calculator) key(alg string) string {
+	if c.multihashNames {
+		return hashAlgsToMultihashNames[alg]
+	}
+	return alg
+}
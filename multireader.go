@@ -0,0 +1,57 @@
+package hasher
+
+import (
+	"context"
+	"io"
+)
+
+// countingReader counts the bytes read from the reader.
+type countingReader struct {
+	r io.Reader
+	n *int64
+}
+
+// Read implements [io.Reader] interface.
+func (r countingReader) Read(p []byte) (n int, err error) {
+	n, err = r.r.Read(p)
+	*r.n += int64(n)
+	return n, err
+}
+
+// MultiReaderChecksumsCounts returns the checksums of given hash algorithms by reading the readers sequentially(like [io.MultiReader]),
+// and the number of bytes read from each reader.
+// The counts are used to report or diagnose truncated parts.
+// ctx: [context.Context].
+// It returns states of the hashes instead of checksums
+// if the context is canceled or the deadline expires.
+// See [ChecksumsBuffer].
+// readers: parts to read.
+// total: total size of all readers. It's used to report the progress.
+// Set it to -1 if its total size is unknown.
+// options: [Option] used to resume previous calculation or report progress.
+// counts: number of bytes read from each reader in current calculation.
+func MultiReaderChecksumsCounts(ctx context.Context, readers []io.Reader, total int64, options ...Option) (written int64, counts []int64, checksums map[string][]byte, err error) {
+	counts = make([]int64, len(readers))
+
+	var wrapped []io.Reader
+	for i, r := range readers {
+		wrapped = append(wrapped, countingReader{r: r, n: &counts[i]})
+	}
+
+	written, checksums, err = Checksums(ctx, io.MultiReader(wrapped...), total, options...)
+	return written, counts, checksums, err
+}
+
+// MultiReaderChecksums returns the checksums of given hash algorithms by reading the readers sequentially(like [io.MultiReader]).
+// ctx: [context.Context].
+// It returns states of the hashes instead of checksums
+// if the context is canceled or the deadline expires.
+// See [ChecksumsBuffer].
+// readers: parts to read.
+// total: total size of all readers. It's used to report the progress.
+// Set it to -1 if its total size is unknown.
+// options: [Option] used to resume previous calculation or report progress.
+// Call [MultiReaderChecksumsCounts] to get the number of bytes read from each reader.
+func MultiReaderChecksums(ctx context.Context, readers []io.Reader, total int64, options ...Option) (written int64, checksums map[string][]byte, err error) {
+	return Checksums(ctx, io.MultiReader(readers...), total, options...)
+}
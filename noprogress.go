@@ -0,0 +1,33 @@
+package hasher
+
+import (
+	"io"
+)
+
+// maxConsecutiveEmptyReads is the max number of consecutive reads returning (0, nil).
+// It's the same as bufio.
+const maxConsecutiveEmptyReads = 100
+
+// noProgressReader guards against misbehaving readers which return (0, nil) repeatedly.
+// It returns [io.ErrNoProgress] after maxConsecutiveEmptyReads consecutive empty reads,
+// instead of spinning forever in the copy loop.
+type noProgressReader struct {
+	r     io.Reader
+	empty int
+}
+
+// Read implements [io.Reader] interface.
+func (r *noProgressReader) Read(p []byte) (n int, err error) {
+	n, err = r.r.Read(p)
+	if n > 0 || err != nil || len(p) == 0 {
+		r.empty = 0
+		return n, err
+	}
+
+	r.empty++
+	if r.empty >= maxConsecutiveEmptyReads {
+		return 0, io.ErrNoProgress
+	}
+
+	return 0, nil
+}
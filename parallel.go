@@ -0,0 +1,67 @@
+package hasher
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// parallel calls fn for each index in [0, n) by at most concurrency goroutines.
+// It uses [runtime.NumCPU] if concurrency <= 0, and starts at most n goroutines.
+// When fn returns an error, it cancels the context passed to other calls of fn and stops dispatching.
+// It returns the first error returned by fn, or the error of ctx.
+func parallel(ctx context.Context, n int, concurrency int, fn func(ctx context.Context, i int) error) error {
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	// No more workers than indexes.
+	concurrency = min(concurrency, n)
+
+	// Cancel other workers when one of them fails.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	ch := make(chan int)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for index := range ch {
+				if err := fn(ctx, index); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	// Dispatch indexes to workers.
+dispatch:
+	for index := 0; index < n; index++ {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case ch <- index:
+		}
+	}
+	close(ch)
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,61 @@
+package hasher
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidChecksumLine indicates that the checksum line can not be parsed.
+	ErrInvalidChecksumLine = errors.New("invalid checksum line")
+)
+
+// ParseAlgs parses the comma-separated hash algorithms(e.g. "md5,sha-256,crc-32").
+// It's used to parse CLI flags or environment variables.
+// Each entry is trimmed, converted to upper case letters and validated.
+// Empty entries are ignored.
+// It returns an error wrapping [ErrUnSupportedHashAlg] and naming the first invalid entry.
+func ParseAlgs(s string) ([]string, error) {
+	var algs []string
+
+	for _, entry := range strings.Split(s, ",") {
+		alg := strings.ToUpper(strings.TrimSpace(entry))
+		if alg == "" {
+			continue
+		}
+
+		if alg != NoneAlg {
+			if _, err := newHash(alg); err != nil {
+				return nil, fmt.Errorf("%w: %q", err, strings.TrimSpace(entry))
+			}
+		}
+
+		algs = append(algs, alg)
+	}
+
+	return algs, nil
+}
+
+// ParseChecksumLine parses a line of checksum files(e.g. output of sha256sum).
+// Supported formats:
+//   - "<hex>  <filename>"(text mode).
+//   - "<hex> *<filename>"(binary mode).
+//   - "<hex>"(bare hex).
+//
+// filename is empty for the bare hex format.
+// It returns [ErrInvalidChecksumLine] if the line can not be parsed.
+func ParseChecksumLine(line string) (sum []byte, filename string, err error) {
+	line = strings.TrimSpace(line)
+
+	hexSum, filename, _ := strings.Cut(line, " ")
+	filename = strings.TrimPrefix(strings.TrimLeft(filename, " "), "*")
+
+	sum, err = hex.DecodeString(hexSum)
+	if err != nil || len(sum) == 0 {
+		return nil, "", ErrInvalidChecksumLine
+	}
+
+	return sum, filename, nil
+}
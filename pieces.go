@@ -0,0 +1,54 @@
+package hasher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+)
+
+var (
+	// ErrInvalidPieceHashes indicates that the length of the piece hashes is not a multiple of SHA-1 checksum size.
+	ErrInvalidPieceHashes = errors.New("invalid piece hashes")
+
+	// ErrTooManyPieces indicates that the file has more pieces than the piece hashes.
+	ErrTooManyPieces = errors.New("too many pieces")
+)
+
+// VerifyPieces verifies the file against the BitTorrent-style piece hashes.
+// ctx: [context.Context].
+// filename: file to verify.
+// pieceLength: length of each piece. The last piece may be shorter.
+// pieceHashes: concatenated 20-byte SHA-1 checksums of the pieces.
+// It returns the pass/fail result of each piece.
+// Pieces missing in the file(e.g. partial download) are failed.
+// It returns [ErrInvalidPieceHashes] if the length of pieceHashes is not a multiple of 20,
+// and [ErrTooManyPieces] if the file has more pieces than pieceHashes.
+func VerifyPieces(ctx context.Context, filename string, pieceLength int64, pieceHashes []byte) ([]bool, error) {
+	digests, err := SplitDigests(pieceHashes, "SHA-1")
+	if err != nil {
+		return nil, ErrInvalidPieceHashes
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	leaves, _, err := ChunkedChecksums(ctx, "SHA-1", f, pieceLength)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]bool, len(digests))
+	if len(leaves) > len(results) {
+		return nil, ErrTooManyPieces
+	}
+
+	for i, leaf := range leaves {
+		results[i] = bytes.Equal(leaf, digests[i])
+	}
+
+	return results, nil
+}
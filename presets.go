@@ -0,0 +1,71 @@
+package hasher
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+var (
+	// algPresetsLock protects algPresets.
+	algPresetsLock sync.RWMutex
+
+	// algPresets maps preset names to hash algorithms.
+	algPresets = map[string][]string{
+		"web":    {"SHA-256", "SHA-1"},
+		"legacy": {"MD5", "SHA-1"},
+		"fast":   {"CRC-32"},
+	}
+
+	// ErrInvalidAlgPreset indicates that the name or the hash algorithms of the preset is invalid.
+	ErrInvalidAlgPreset = errors.New("invalid hash algorithm preset")
+)
+
+// AlgPreset returns the hash algorithms of the named preset.
+// name: name of the preset. It's case-insensitive.
+// Built-in presets:
+//   - "web": SHA-256, SHA-1.
+//   - "legacy": MD5, SHA-1.
+//   - "fast": CRC-32.
+//
+// It returns false if the preset is not found.
+func AlgPreset(name string) ([]string, bool) {
+	algPresetsLock.RLock()
+	defer algPresetsLock.RUnlock()
+
+	algs, ok := algPresets[strings.ToLower(name)]
+	if !ok {
+		return nil, false
+	}
+
+	return append([]string{}, algs...), true
+}
+
+// RegisterAlgPreset registers a named preset of hash algorithms.
+// It overwrites the preset with the same name.
+// name: name of the preset. It's case-insensitive.
+// algs: hash algorithms of the preset.
+// It returns [ErrInvalidAlgPreset] if name is empty or algs is empty,
+// and [ErrUnSupportedHashAlg] if any hash algorithm is not supported.
+func RegisterAlgPreset(name string, algs []string) error {
+	if name == "" || len(algs) == 0 {
+		return ErrInvalidAlgPreset
+	}
+
+	var normalized []string
+	for _, alg := range algs {
+		alg = strings.ToUpper(alg)
+		if alg != NoneAlg {
+			if _, err := newHash(alg); err != nil {
+				return err
+			}
+		}
+		normalized = append(normalized, alg)
+	}
+
+	algPresetsLock.Lock()
+	defer algPresetsLock.Unlock()
+
+	algPresets[strings.ToLower(name)] = normalized
+	return nil
+}
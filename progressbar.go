@@ -0,0 +1,49 @@
+package hasher
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// spinnerFrames are the frames of the indeterminate spinner.
+const spinnerFrames = `|/-\`
+
+// ProgressBarCallback returns a callback which renders a textual progress bar to w.
+// It's used by CLI tools to show the progress without a UI library.
+// Pass it to [OnHash] to get an option.
+// w: writer to render the progress bar(e.g. os.Stderr).
+// width: number of characters of the bar. It uses 20 if width <= 0.
+// It renders "[####----] 42%" and uses carriage return to redraw in place,
+// and writes a newline when it's completed(100%).
+// If the total size is unknown, it renders an indeterminate spinner with the number of bytes(e.g. "[|] 1024 bytes").
+func ProgressBarCallback(w io.Writer, width int) OnHashFunc {
+	if width <= 0 {
+		width = 20
+	}
+
+	frame := 0
+	done := false
+
+	return func(total, prev, current int64, percent float32) {
+		if done {
+			return
+		}
+
+		if total < 0 {
+			fmt.Fprintf(w, "\r[%c] %d bytes", spinnerFrames[frame%len(spinnerFrames)], prev+current)
+			frame++
+			return
+		}
+
+		p := min(max(percent, 0), 100)
+		filled := int(float32(width) * p / 100)
+
+		fmt.Fprintf(w, "\r[%s%s] %d%%", strings.Repeat("#", filled), strings.Repeat("-", width-filled), int(p))
+
+		if prev+current >= total {
+			done = true
+			fmt.Fprintln(w)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package hasher
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// rateLimitedReader limits the read rate of the reader by a token bucket.
+type rateLimitedReader struct {
+	ctx    context.Context
+	r      io.Reader
+	rate   int64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimitedReader creates a rateLimitedReader.
+// rate: max number of bytes read per second.
+// The bucket is empty at start and its capacity is rate bytes(1 second burst).
+func newRateLimitedReader(ctx context.Context, r io.Reader, rate int64) *rateLimitedReader {
+	return &rateLimitedReader{
+		ctx:  ctx,
+		r:    r,
+		rate: rate,
+		last: nowFunc(),
+	}
+}
+
+// Read implements [io.Reader] interface.
+// It sleeps after reading until the tokens are refilled,
+// and returns the error of the context immediately if it's canceled while sleeping.
+func (r *rateLimitedReader) Read(p []byte) (n int, err error) {
+	if int64(len(p)) > r.rate {
+		p = p[:r.rate]
+	}
+
+	n, err = r.r.Read(p)
+	if n > 0 {
+		if werr := r.wait(n); werr != nil {
+			return n, werr
+		}
+	}
+
+	return n, err
+}
+
+// wait takes n tokens from the bucket and sleeps if there're not enough tokens.
+func (r *rateLimitedReader) wait(n int) error {
+	// Refill the bucket.
+	now := nowFunc()
+	r.tokens += now.Sub(r.last).Seconds() * float64(r.rate)
+	r.tokens = min(r.tokens, float64(r.rate))
+	r.last = now
+
+	r.tokens -= float64(n)
+	if r.tokens >= 0 {
+		return nil
+	}
+
+	d := time.Duration(-r.tokens / float64(r.rate) * float64(time.Second))
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-r.ctx.Done():
+		return r.ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
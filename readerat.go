@@ -0,0 +1,90 @@
+package hasher
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	// ErrInvalidAlignSize indicates that the align size is invalid.
+	ErrInvalidAlignSize = errors.New("invalid align size")
+)
+
+// alignedReader implements [io.Reader] by issuing aligned ReadAt calls to an [io.ReaderAt].
+type alignedReader struct {
+	ra      io.ReaderAt
+	size    int64
+	off     int64
+	align   int64
+	buf     []byte
+	pending []byte
+}
+
+// Read implements [io.Reader] interface.
+// Each ReadAt call reads from off to the next aligned boundary(or the end).
+func (r *alignedReader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		if r.off >= r.size {
+			return 0, io.EOF
+		}
+
+		n := min(r.align-r.off%r.align, r.size-r.off)
+		m, err := r.ra.ReadAt(r.buf[:n], r.off)
+		if int64(m) < n {
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+
+		r.off += n
+		r.pending = r.buf[:n]
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// ReaderAtChecksums returns the checksums of given hash algorithms by issuing aligned ReadAt calls to ra.
+// It's used by storage backends(e.g. object stores) where aligned, fixed-size ReadAt calls perform better than sequential Read.
+// ctx: [context.Context].
+// It returns states of the hashes instead of checksums
+// if the context is canceled or the deadline expires.
+// Users can call [States] to get an option and pass it to the next call of [ReaderAtChecksums],
+// to resume previous calculation.
+// ra: [io.ReaderAt] to read.
+// size: total size of ra. It returns [ErrInvalidSize] if it's negative.
+// alignSize: size of each aligned ReadAt call. The final block may be shorter.
+// It returns [ErrInvalidAlignSize] if alignSize <= 0.
+// options: [Option] used to resume previous calculation or report progress.
+func ReaderAtChecksums(ctx context.Context, ra io.ReaderAt, size int64, alignSize int64, options ...Option) (written int64, checksums map[string][]byte, err error) {
+	if alignSize <= 0 {
+		return 0, nil, ErrInvalidAlignSize
+	}
+
+	if size < 0 {
+		return 0, nil, ErrInvalidSize
+	}
+
+	// Set options.
+	c := &calculator{}
+	for _, option := range options {
+		option(c)
+	}
+
+	r := &alignedReader{
+		ra:    ra,
+		size:  size,
+		align: alignSize,
+		buf:   make([]byte, alignSize),
+	}
+
+	// Resume previous calculation by setting the offset.
+	if c.hashed > 0 && len(c.states) > 0 {
+		r.off = c.hashed
+	}
+
+	return Checksums(ctx, r, size, options...)
+}
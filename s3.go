@@ -0,0 +1,41 @@
+package hasher
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"os"
+)
+
+// S3MultipartETag returns the ETag of the file uploaded to S3 by multipart upload.
+// It's used to verify S3 uploads.
+// The ETag is hex(MD5(MD5(part 1) || MD5(part 2) || ... || MD5(part N))) + "-N".
+// If the file has only one part(size <= partSize), it returns the hex MD5 checksum of the file.
+// ctx: [context.Context].
+// filename: file to calculate the ETag.
+// partSize: part size used by the multipart upload.
+func S3MultipartETag(ctx context.Context, filename string, partSize int64) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	leaves, root, err := ChunkedChecksums(ctx, "MD5", f, partSize)
+	if err != nil {
+		return "", err
+	}
+
+	// Single part or empty file.
+	if len(leaves) <= 1 {
+		return hex.EncodeToString(root), nil
+	}
+
+	h := md5.New()
+	for _, leaf := range leaves {
+		h.Write(leaf)
+	}
+
+	return fmt.Sprintf("%x-%d", h.Sum(nil), len(leaves)), nil
+}
@@ -0,0 +1,54 @@
+package hasher
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// selfTestInput is the input of the known-answer tests.
+const selfTestInput = "abc"
+
+// selfTestVectors stores the expected checksums of selfTestInput.
+// key: algorithm, value: hex checksum.
+// Hash algorithms enabled by build tags add their test vectors in init.
+var selfTestVectors = map[string]string{
+	"MD5":     "900150983cd24fb0d6963f7d28e17f72",
+	"SHA-1":   "a9993e364706816aba3e25717850c26c9cd0d89d",
+	"SHA-256": "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+	"SHA-512": "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+	"CRC-32":  "352441c2",
+}
+
+// SelfTest verifies the registered hash algorithms against the known test vectors.
+// It's used for FIPS-style startup validation.
+// For each hash algorithm with a known test vector, it hashes a fixed input and compares the checksum with the expected one.
+// Built-in hash algorithms and the ones enabled by build tags(e.g. WHIRLPOOL) have known test vectors.
+// Hash algorithms registered by [RegisterHashAlg] have no known test vectors and are skipped.
+// It returns an aggregated error of all mismatches, or nil if all tests pass.
+func SelfTest() error {
+	var errs []error
+
+	for _, alg := range SupportedHashAlgs() {
+		expected, ok := selfTestVectors[alg]
+		if !ok {
+			continue
+		}
+
+		h, err := newHash(alg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", alg, err))
+			continue
+		}
+
+		h.Write([]byte(selfTestInput))
+		want, _ := hex.DecodeString(expected)
+
+		if subtle.ConstantTimeCompare(h.Sum(nil), want) != 1 {
+			errs = append(errs, fmt.Errorf("%v: known-answer test failed", alg))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,105 @@
+package hasher
+
+import (
+	"encoding"
+	"hash"
+	"strings"
+)
+
+// Session computes checksums incrementally by feeding data manually.
+// It's the imperative counterpart to the reader-based functions(e.g. [Checksums])
+// and it's used for data arriving as discrete messages.
+// It has no context or I/O.
+// Call [NewSession] to create a session.
+type Session struct {
+	hashes  map[string]hash.Hash
+	written int64
+}
+
+// NewSession creates a new [Session].
+// algs: name of hash algorithms. See [Algs].
+// If no hash algorithms specified, it uses [DefaultAlgs].
+func NewSession(algs []string) (*Session, error) {
+	if len(algs) == 0 {
+		algs = DefaultAlgs
+	}
+
+	s := &Session{hashes: make(map[string]hash.Hash)}
+
+	for _, alg := range algs {
+		// Use upper case letters for algorithm.
+		alg = strings.ToUpper(alg)
+
+		// "NONE" algorithm produces no checksum.
+		if alg == NoneAlg {
+			continue
+		}
+
+		h, err := newHash(alg)
+		if err != nil {
+			return nil, err
+		}
+		s.hashes[alg] = h
+	}
+
+	return s, nil
+}
+
+// Write implements [io.Writer] interface.
+// It writes p to all the hashes.
+// It never returns an error.
+func (s *Session) Write(p []byte) (n int, err error) {
+	for _, h := range s.hashes {
+		h.Write(p)
+	}
+	s.written += int64(len(p))
+
+	return len(p), nil
+}
+
+// Finalize returns the checksums of the data written so far.
+// It does not change the state of the session,
+// it may be called multiple times and more data can be written after it's called.
+func (s *Session) Finalize() map[string][]byte {
+	checksums := make(map[string][]byte)
+
+	for alg, h := range s.hashes {
+		checksums[alg] = h.Sum(nil)
+	}
+
+	return checksums
+}
+
+// Reset resets the session to its initial state.
+func (s *Session) Reset() {
+	for _, h := range s.hashes {
+		h.Reset()
+	}
+	s.written = 0
+}
+
+// Snapshot returns the current states of the hashes and the number of bytes written so far,
+// without changing the state of the session.
+// It's the checkpoint primitive of long-running calculations(e.g. save the states to disk periodically).
+// Pass the states and the offset to [States] to resume the calculation by [Checksums] or other functions.
+// Only hash algorithms implementing [encoding.BinaryMarshaler] are supported(all the built-in ones),
+// otherwise it returns [ErrNotBinaryMarshaler].
+func (s *Session) Snapshot() (states map[string][]byte, written int64, err error) {
+	states = make(map[string][]byte)
+
+	for alg, h := range s.hashes {
+		marshaler, ok := h.(encoding.BinaryMarshaler)
+		if !ok {
+			return nil, 0, ErrNotBinaryMarshaler
+		}
+
+		state, err := marshaler.MarshalBinary()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		states[alg] = state
+	}
+
+	return states, s.written, nil
+}
@@ -0,0 +1,98 @@
+package hasher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteSidecars computes the checksums of the file and writes each checksum to a sidecar file.
+// It's used by archival jobs to persist checksums as soon as the calculation is done.
+// The sidecar file is filename + [SidecarExt](alg)(e.g. file.iso.sha256),
+// and its content is a line formatted by [FormatChecksumLine] with the base name of the file.
+// Each sidecar file is written atomically by writing a temporary file in the same directory and renaming it.
+// It has the same permission bits as the file without the execute bits.
+// ctx: [context.Context].
+// filename: file to calculate the hash checksums.
+// options: [Option] used to set hash algorithms or report progress.
+// Sidecar files are written only for the hash algorithms set by [Algs]. [NoneAlg] is skipped.
+// It returns [ErrNonStandardChecksums] if any option changes the checksums or their keys,
+// because the sidecar files must contain the standard checksums.
+// See [AppendLength], [Prefix], [SizeEntry] and [MultihashNames].
+// If the context is canceled or the deadline expires, it returns the error and no sidecar files are written.
+func WriteSidecars(ctx context.Context, filename string, options ...Option) (checksums map[string][]byte, err error) {
+	// Set options.
+	c := &calculator{}
+	for _, option := range options {
+		option(c)
+	}
+
+	if len(c.algs) == 0 {
+		c.algs = DefaultAlgs
+	}
+
+	if c.nonStandard() {
+		return nil, ErrNonStandardChecksums
+	}
+
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	_, checksums, err = FileChecksums(ctx, filename, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, alg := range c.algs {
+		alg = strings.ToUpper(alg)
+
+		sum, ok := checksums[alg]
+		if !ok {
+			continue
+		}
+
+		line := FormatChecksumLine(sum, filepath.Base(filename))
+		if err = writeFileAtomic(filename+SidecarExt(alg), []byte(line), fi.Mode().Perm()&^0111); err != nil {
+			return nil, err
+		}
+	}
+
+	return checksums, nil
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory and renames it to filename.
+// perm: permission bits of the file. [os.CreateTemp] creates the temporary file with 0600.
+func writeFileAtomic(filename string, data []byte, perm os.FileMode) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	if _, err = f.Write(data); err != nil {
+		return err
+	}
+
+	if err = f.Chmod(perm); err != nil {
+		return err
+	}
+
+	if err = f.Sync(); err != nil {
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), filename)
+}
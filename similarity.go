@@ -0,0 +1,135 @@
+package hasher
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	// ErrInvalidWindowSize indicates that the window size is invalid.
+	ErrInvalidWindowSize = errors.New("invalid window size")
+)
+
+// contextReader returns the error of the context before each read.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read implements [io.Reader] interface.
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.r.Read(p)
+}
+
+// rollingSum is the weak rolling checksum used by rsync.
+type rollingSum struct {
+	a, b uint32
+	l    uint32
+}
+
+// init computes the checksum of the window.
+func (s *rollingSum) init(window []byte) {
+	s.a, s.b, s.l = 0, 0, uint32(len(window))
+	for i, x := range window {
+		s.a += uint32(x)
+		s.b += (s.l - uint32(i)) * uint32(x)
+	}
+}
+
+// roll removes out from the head of the window and appends in to the tail.
+func (s *rollingSum) roll(out, in byte) {
+	s.a += uint32(in) - uint32(out)
+	s.b += s.a - s.l*uint32(out)
+}
+
+// sum returns the checksum.
+func (s *rollingSum) sum() uint32 {
+	return (s.a & 0xffff) | (s.b << 16)
+}
+
+// SimilarityScore estimates the fraction of the content blocks of a which are also in b.
+// It's a heuristic for "are these files roughly similar" checks.
+// It's NOT cryptographic and NOT an exact comparison, use [StreamsEqual] for exact comparison.
+// a is split into non-overlapping blocks of windowSize bytes,
+// and b is scanned by the weak rolling checksum of rsync at every offset to find the blocks.
+// Matches are based on the weak checksum only, so false positives are possible.
+// ctx: [context.Context].
+// a, b: streams to compare.
+// windowSize: size of the blocks. It returns [ErrInvalidWindowSize] if windowSize <= 0.
+// It returns a score in [0, 1]. Inputs shorter than windowSize have no full blocks and score 0.
+func SimilarityScore(ctx context.Context, a, b io.Reader, windowSize int) (float64, error) {
+	if windowSize <= 0 {
+		return 0, ErrInvalidWindowSize
+	}
+
+	// Compute the checksums of the blocks of a.
+	blocks := make(map[uint32]int)
+	total := 0
+
+	ra := contextReader{ctx: ctx, r: a}
+	window := make([]byte, windowSize)
+	for {
+		if _, err := io.ReadFull(ra, window); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			return 0, err
+		}
+
+		var s rollingSum
+		s.init(window)
+		blocks[s.sum()]++
+		total++
+	}
+
+	if total == 0 {
+		return 0, nil
+	}
+
+	// Scan b by the rolling checksum.
+	rb := bufio.NewReader(contextReader{ctx: ctx, r: b})
+	matched := 0
+
+	for {
+		// Fill a new window.
+		if _, err := io.ReadFull(rb, window); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			return 0, err
+		}
+
+		var s rollingSum
+		s.init(window)
+
+		head := 0
+		for {
+			if n := blocks[s.sum()]; n > 0 {
+				// Start a new window after the matched block.
+				blocks[s.sum()] = n - 1
+				matched++
+				break
+			}
+
+			in, err := rb.ReadByte()
+			if err != nil {
+				if err == io.EOF {
+					return float64(matched) / float64(total), nil
+				}
+				return 0, err
+			}
+
+			s.roll(window[head], in)
+			window[head] = in
+			head = (head + 1) % windowSize
+		}
+	}
+
+	return float64(matched) / float64(total), nil
+}
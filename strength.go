@@ -0,0 +1,112 @@
+package hasher
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+)
+
+// strengthOrder is the hash algorithms ordered from the strongest to the weakest.
+var strengthOrder = []string{"SHA-512", "WHIRLPOOL", "SHA-256", "SHA-1", "MD5", "CRC-32"}
+
+var (
+	// cryptographicAlgs is the set of cryptographic hash algorithms.
+	cryptographicAlgs = map[string]bool{
+		"MD5":       true,
+		"SHA-1":     true,
+		"SHA-256":   true,
+		"SHA-512":   true,
+		"WHIRLPOOL": true,
+	}
+
+	// brokenAlgs is the set of cryptographic hash algorithms with practical collision attacks.
+	brokenAlgs = map[string]bool{
+		"MD5":   true,
+		"SHA-1": true,
+	}
+)
+
+// RecommendedAlg is the hash algorithm recommended to replace weak ones.
+const RecommendedAlg = "SHA-256"
+
+// IsCryptographic reports whether the hash algorithm is designed as a cryptographic hash function.
+// MD5 and SHA-1 are cryptographic but broken(practical collision attacks exist).
+// CRC-32 is not cryptographic.
+// alg: hash algorithm. It's case-insensitive.
+func IsCryptographic(alg string) bool {
+	return cryptographicAlgs[strings.ToUpper(alg)]
+}
+
+// MigrationAdvice returns the upgrade guidance of a stored checksum's hash algorithm.
+// It's used when migrating checksum databases.
+// oldAlg: hash algorithm of the stored checksum. It's case-insensitive.
+// supported: whether the hash algorithm is still supported(registered).
+// recommendedReplacement: [RecommendedAlg] if the hash algorithm is not cryptographic(see [IsCryptographic]),
+// broken(MD5, SHA-1) or not supported. Empty if it's still safe.
+func MigrationAdvice(oldAlg string) (supported bool, recommendedReplacement string) {
+	alg := strings.ToUpper(oldAlg)
+
+	_, err := newHash(alg)
+	supported = err == nil
+
+	if !supported || !IsCryptographic(alg) || brokenAlgs[alg] {
+		return supported, RecommendedAlg
+	}
+
+	return supported, ""
+}
+
+// StrongestAlg returns the strongest supported hash algorithm in available.
+// The strength ordering(from the strongest to the weakest) is: SHA-512, WHIRLPOOL, SHA-256, SHA-1, MD5, CRC-32.
+// Other supported hash algorithms(e.g. registered by [RegisterHashAlg]) are weaker than all of them.
+// available: hash algorithms to select from. They're case-insensitive.
+// It returns false if none of available is supported.
+func StrongestAlg(available []string) (string, bool) {
+	best := ""
+	bestRank := len(strengthOrder) + 1
+
+	for _, alg := range available {
+		alg = strings.ToUpper(alg)
+		if alg == NoneAlg {
+			continue
+		}
+
+		if _, err := newHash(alg); err != nil {
+			continue
+		}
+
+		rank := slices.Index(strengthOrder, alg)
+		if rank < 0 {
+			rank = len(strengthOrder)
+		}
+
+		if rank < bestRank {
+			best, bestRank = alg, rank
+		}
+	}
+
+	return best, best != ""
+}
+
+// BestChecksum selects the strongest supported hash algorithm in available by [StrongestAlg],
+// and computes only the checksum of it by reading r.
+// It's used to verify against a checksum file which may contain any of several hash algorithms,
+// without wasting cycles on weak ones.
+// ctx: [context.Context].
+// available: hash algorithms to select from.
+// r: read the bytes from r and calculate the checksum.
+// It returns [ErrUnSupportedHashAlg] if none of available is supported.
+func BestChecksum(ctx context.Context, available []string, r io.Reader) (alg string, sum []byte, err error) {
+	alg, ok := StrongestAlg(available)
+	if !ok {
+		return "", nil, ErrUnSupportedHashAlg
+	}
+
+	_, checksums, err := Checksums(ctx, r, -1, Algs([]string{alg}))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return alg, checksums[alg], nil
+}
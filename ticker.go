@@ -0,0 +1,78 @@
+package hasher
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/northbright/iocopy/progress"
+)
+
+// reporter reports progress of the calculation.
+// It's implemented by [progress.Progress] and tickProgress.
+type reporter interface {
+	io.Writer
+	Start(ctx context.Context, chExit <-chan struct{})
+}
+
+// tickProgress implements the reporter interface.
+// Unlike [progress.Progress], it calls the callback at every tick even if no new bytes are written.
+type tickProgress struct {
+	total    int64
+	prev     int64
+	current  atomic.Int64
+	fn       progress.OnWrittenFunc
+	interval time.Duration
+}
+
+// newTickProgress creates a tickProgress.
+// It uses [progress.DefaultInterval] if interval <= 0.
+func newTickProgress(total, prev int64, fn progress.OnWrittenFunc, interval time.Duration) *tickProgress {
+	if interval <= 0 {
+		interval = progress.DefaultInterval
+	}
+
+	return &tickProgress{
+		total:    total,
+		prev:     prev,
+		fn:       fn,
+		interval: interval,
+	}
+}
+
+// Write implements [io.Writer] interface.
+func (p *tickProgress) Write(b []byte) (n int, err error) {
+	n = len(b)
+	p.current.Add(int64(n))
+	return n, nil
+}
+
+// callback calls the callback function to report progress.
+func (p *tickProgress) callback() {
+	current := p.current.Load()
+	p.fn(p.total, p.prev, current, progress.Percent(p.total, p.prev, current))
+}
+
+// Start starts a new goroutine and tick to call the callback to report progress.
+// It exits when it receives data from ctx.Done() or chExit.
+func (p *tickProgress) Start(ctx context.Context, chExit <-chan struct{}) {
+	ticker := time.NewTicker(p.interval)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-chExit:
+				p.callback()
+				return
+			case <-ctx.Done():
+				p.callback()
+				return
+			case <-ticker.C:
+				p.callback()
+			}
+		}
+	}()
+}
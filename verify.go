@@ -0,0 +1,155 @@
+package hasher
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ErrNoExpectedChecksums indicates that no expected checksums are provided.
+	ErrNoExpectedChecksums = errors.New("no expected checksums")
+
+	// ErrNoMatchingChecksum indicates that no line of the digest file matches the file.
+	ErrNoMatchingChecksum = errors.New("no matching checksum")
+)
+
+// maxDigestFileSize is the max size of the digest file to fetch.
+const maxDigestFileSize = 1024 * 1024
+
+// fetchChecksum fetches the digest file from the URL and returns the checksum of the file.
+// It uses the line matching the base name of filename,
+// or the bare checksum if the digest file contains a single line without a filename.
+// It returns [ErrNoMatchingChecksum] if no line matches.
+func fetchChecksum(ctx context.Context, digestURL, filename string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, digestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status of digest URL: %v", resp.Status)
+	}
+
+	var (
+		bare  []byte
+		lines int
+	)
+	base := filepath.Base(filename)
+
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxDigestFileSize))
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		sum, name, err := ParseChecksumLine(scanner.Text())
+		if err != nil {
+			return nil, err
+		}
+
+		if name != "" && filepath.Base(name) == base {
+			return sum, nil
+		}
+
+		lines++
+		if name == "" {
+			bare = sum
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if lines == 0 {
+		return nil, ErrInvalidChecksumLine
+	}
+
+	// Never compare against the checksum of another file.
+	if lines != 1 || bare == nil {
+		return nil, ErrNoMatchingChecksum
+	}
+
+	return bare, nil
+}
+
+// VerifyFileAgainstURL verifies the file against the checksum fetched from the digest URL(e.g. a .sha256 file).
+// It's used by self-updating tools to verify downloaded releases.
+// ctx: [context.Context]. It's used by both the HTTP request and the calculation.
+// filename: file to verify.
+// alg: hash algorithm of the checksum.
+// digestURL: URL of the digest file.
+// The digest file may contain "<hex>  <filename>" lines or a bare hex checksum. See [ParseChecksumLine].
+// It uses the line matching the base name of filename,
+// or the bare checksum if the digest file contains a single line without a filename.
+// It returns [ErrNoMatchingChecksum] if no line matches.
+// It returns true if the checksums match.
+func VerifyFileAgainstURL(ctx context.Context, filename, alg, digestURL string) (bool, error) {
+	expected, err := fetchChecksum(ctx, digestURL, filename)
+	if err != nil {
+		return false, err
+	}
+
+	_, checksums, err := FileChecksums(ctx, filename, Algs([]string{alg}))
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare(checksums[strings.ToUpper(alg)], expected) == 1, nil
+}
+
+// ChecksumsAndVerify computes the checksums of exactly the hash algorithms in expected by a single read of r,
+// and verifies them against the expected ones.
+// It's used for non-seekable readers which can't be read twice.
+// ctx: [context.Context].
+// r: read the bytes from r and calculate the hash checksums.
+// expected: expected hex checksums. key: algorithm, value: hex checksum.
+// checksums: computed checksums. key: algorithm(upper case), value: checksum.
+// It returns [ErrNoExpectedChecksums] if expected is empty.
+// matches: match results of each algorithm. key: algorithm(upper case). Checksums are compared in constant time.
+func ChecksumsAndVerify(ctx context.Context, r io.Reader, expected map[string]string) (checksums map[string][]byte, matches map[string]bool, err error) {
+	var algs []string
+	sums := make(map[string][]byte)
+
+	for alg, hexSum := range expected {
+		alg = strings.ToUpper(alg)
+
+		sum, err := hex.DecodeString(hexSum)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%v: %w", alg, err)
+		}
+
+		algs = append(algs, alg)
+		sums[alg] = sum
+	}
+
+	if len(algs) == 0 {
+		return nil, nil, ErrNoExpectedChecksums
+	}
+
+	_, checksums, err = Checksums(ctx, r, -1, Algs(algs))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	matches = make(map[string]bool)
+	for alg, sum := range sums {
+		matches[alg] = subtle.ConstantTimeCompare(checksums[alg], sum) == 1
+	}
+
+	return checksums, matches, nil
+}
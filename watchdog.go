@@ -0,0 +1,104 @@
+package hasher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrThroughputTooLow indicates that the read rate stays below the threshold set by [MinThroughput] for the window.
+	ErrThroughputTooLow = errors.New("throughput too low")
+)
+
+// MinThroughput returns an option to abort the calculation if the read rate stays below the threshold for the window.
+// It's used to give up a stalled source(e.g. a flaky CDN) and retry elsewhere.
+// bytesPerSec: min number of bytes read per second. No watchdog if it's <= 0.
+// window: duration of the rolling window to calculate the read rate. No watchdog if it's <= 0.
+// The calculation is stopped like a canceled context:
+// it returns the states of the hashes and an error which wraps [ErrThroughputTooLow] and [context.Canceled].
+// It never fires before a whole window elapses, so inputs which finish within the window are never aborted.
+func MinThroughput(bytesPerSec int64, window time.Duration) Option {
+	return func(c *calculator) {
+		c.minThroughput = bytesPerSec
+		c.throughputWindow = window
+	}
+}
+
+// watchedReader counts the bytes read from the reader atomically.
+// The count is read by the watchdog goroutine.
+type watchedReader struct {
+	r io.Reader
+	n atomic.Int64
+}
+
+// Read implements [io.Reader] interface.
+func (r *watchedReader) Read(p []byte) (n int, err error) {
+	n, err = r.r.Read(p)
+	r.n.Add(int64(n))
+	return n, err
+}
+
+// throughputSample is the number of bytes read at the time.
+type throughputSample struct {
+	t time.Time
+	n int64
+}
+
+// startWatchdog starts a goroutine to monitor the read rate of r over the rolling window.
+// It cancels the returned context with [ErrThroughputTooLow] as the cause if the rate is below bytesPerSec.
+// Call the returned stop function to cancel the context and wait for the goroutine to exit.
+func startWatchdog(ctx context.Context, r *watchedReader, bytesPerSec int64, window time.Duration) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	done := make(chan struct{})
+
+	// Sample the count 10 times per window.
+	interval := window / 10
+	if interval <= 0 {
+		interval = window
+	}
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		samples := []throughputSample{{t: nowFunc(), n: r.n.Load()}}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				now := nowFunc()
+				n := r.n.Load()
+				samples = append(samples, throughputSample{t: now, n: n})
+
+				// Keep the latest sample taken before the window as the base.
+				start := now.Add(-window)
+				for len(samples) >= 2 && !samples[1].t.After(start) {
+					samples = samples[1:]
+				}
+
+				// Wait until a whole window elapses.
+				base := samples[0]
+				if base.t.After(start) {
+					continue
+				}
+
+				if float64(n-base.n) < float64(bytesPerSec)*now.Sub(base.t).Seconds() {
+					cancel(ErrThroughputTooLow)
+					return
+				}
+			}
+		}
+	}()
+
+	return ctx, func() {
+		cancel(nil)
+		<-done
+	}
+}
@@ -0,0 +1,15 @@
+//go:build hasher_whirlpool
+
+package hasher
+
+import (
+	"github.com/jzelinskie/whirlpool"
+)
+
+// Register WHIRLPOOL hash algorithm when build with hasher_whirlpool tag.
+// It's opt-in and does not add the algorithm to the default build.
+// The hash does not implement [encoding.BinaryMarshaler], so the calculation can not be resumed.
+func init() {
+	hashAlgsToNewFuncs["WHIRLPOOL"] = whirlpool.New
+	selfTestVectors["WHIRLPOOL"] = "4e2448a4c6f486bb16b6562c73b4020bf3043e3a731bce721ae1b303d97e6d4c7181eebdb6c57e277d0e34957114cbd6c797fc9d95d8b582d225292076d4eef5"
+}
@@ -0,0 +1,36 @@
+//go:build hasher_whirlpool
+
+package hasher_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/northbright/hasher"
+)
+
+func TestWhirlpool(t *testing.T) {
+	for _, c := range []struct {
+		input string
+		want  string
+	}{
+		{"", "19fa61d75522a4669b44e39c1d2e1726c530232130d407f89afee0964997f7a73e83be698b288febcf88e3e03c4f0757ea8964e59b63d93708b138cc42a66eb3"},
+		{"abc", "4e2448a4c6f486bb16b6562c73b4020bf3043e3a731bce721ae1b303d97e6d4c7181eebdb6c57e277d0e34957114cbd6c797fc9d95d8b582d225292076d4eef5"},
+		{"The quick brown fox jumps over the lazy dog", "b97de512e91e3828b40d2b0fdce9ceb3c4a71f9bea8d88e75c4fa854df36725fd2b52eb6544edcacd6f8beddfea403cb55ae31f03ad62a5ef54e42ee82c3fb35"},
+	} {
+		_, checksums, err := hasher.Checksums(context.Background(), strings.NewReader(c.input), int64(len(c.input)), hasher.Algs([]string{"WHIRLPOOL"}))
+		if err != nil {
+			t.Fatalf("hasher.Checksums() error: %v", err)
+		}
+
+		if got := fmt.Sprintf("%x", checksums["WHIRLPOOL"]); got != c.want {
+			t.Errorf("%q: got %s, want %s", c.input, got, c.want)
+		}
+	}
+
+	if err := hasher.SelfTest(); err != nil {
+		t.Errorf("hasher.SelfTest() error: %v", err)
+	}
+}